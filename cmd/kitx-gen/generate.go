@@ -0,0 +1,105 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"go/format"
+	"strings"
+	"text/template"
+)
+
+// config is the configuration of the generator.
+type config struct {
+	// Prefix is prepended to method names to form operation names.
+	Prefix string
+
+	// HTTP enables generating HTTP transport stubs.
+	HTTP bool
+
+	// GRPC enables generating gRPC transport stubs.
+	GRPC bool
+}
+
+// generatedFile is a generated source file.
+type generatedFile struct {
+	Name    string
+	Content []byte
+}
+
+type templateData struct {
+	service
+
+	Prefix string
+}
+
+// generate generates source files for a service.
+func generate(svc service, c config) ([]generatedFile, error) {
+	data := templateData{
+		service: svc,
+		Prefix:  c.Prefix,
+	}
+
+	basename := snakeCase(svc.Name)
+
+	files := []struct {
+		name     string
+		template *template.Template
+		enabled  bool
+	}{
+		{basename + "_endpoints.gen.go", endpointsTemplate, true},
+		{basename + "_http.gen.go", httpTemplate, c.HTTP},
+		{basename + "_grpc.gen.go", grpcTemplate, c.GRPC},
+	}
+
+	var generated []generatedFile
+
+	for _, file := range files {
+		if !file.enabled {
+			continue
+		}
+
+		var buf bytes.Buffer
+
+		if err := file.template.Execute(&buf, data); err != nil {
+			return nil, fmt.Errorf("generating %s: %w", file.name, err)
+		}
+
+		content, err := format.Source(buf.Bytes())
+		if err != nil {
+			return nil, fmt.Errorf("formatting %s: %w", file.name, err)
+		}
+
+		generated = append(generated, generatedFile{Name: file.name, Content: content})
+	}
+
+	return generated, nil
+}
+
+// snakeCase converts a Go identifier to snake case (eg. "TodoService" becomes "todo_service").
+func snakeCase(name string) string {
+	var b strings.Builder
+
+	for i, r := range name {
+		if r >= 'A' && r <= 'Z' {
+			// Do not split initialisms (eg. "HTTPService" becomes "http_service")
+			if i > 0 && (!isUpper(name[i-1]) || (i+1 < len(name) && !isUpper(name[i+1]))) {
+				b.WriteByte('_')
+			}
+
+			r += 'a' - 'A'
+		}
+
+		b.WriteRune(r)
+	}
+
+	return b.String()
+}
+
+func isUpper(c byte) bool {
+	return c >= 'A' && c <= 'Z'
+}
+
+// isStdlib checks if an import path belongs to the standard library.
+func isStdlib(path string) bool {
+	return !strings.Contains(strings.SplitN(path, "/", 2)[0], ".")
+}
@@ -0,0 +1,198 @@
+package main
+
+import (
+	"bytes"
+	"flag"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// nolint: gochecknoglobals
+var update = flag.Bool("update", false, "update golden files")
+
+func TestGenerate(t *testing.T) {
+	tests := []struct {
+		dir      string
+		typeName string
+		config   config
+		files    int
+	}{
+		{
+			dir:      "testdata/todo",
+			typeName: "Service",
+			config:   config{Prefix: "todo.", HTTP: true, GRPC: true},
+			files:    3,
+		},
+		{
+			dir:      "testdata/queue",
+			typeName: "Forwarder",
+			config:   config{Prefix: "queue."},
+			files:    1,
+		},
+	}
+
+	for _, test := range tests {
+		test := test
+
+		t.Run(test.dir, func(t *testing.T) {
+			svc, err := parseService(test.dir, test.typeName)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			files, err := generate(svc, test.config)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if want, have := test.files, len(files); want != have {
+				t.Fatalf("unexpected number of generated files\nexpected: %d\nactual:   %d", want, have)
+			}
+
+			for _, file := range files {
+				file := file
+
+				t.Run(file.Name, func(t *testing.T) {
+					golden := filepath.Join(test.dir, file.Name)
+
+					if *update {
+						if err := os.WriteFile(golden, file.Content, 0o644); err != nil { // nolint: gosec
+							t.Fatal(err)
+						}
+					}
+
+					want, err := os.ReadFile(golden)
+					if err != nil {
+						t.Fatal(err)
+					}
+
+					if !bytes.Equal(want, file.Content) {
+						t.Errorf("generated code does not match %s (run go test -update to update golden files)\n%s", golden, file.Content)
+					}
+				})
+			}
+		})
+	}
+}
+
+func TestGenerate_Compiles(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping compilation in short mode")
+	}
+
+	cmd := exec.Command("go", "vet", "./testdata/todo", "./testdata/queue")
+
+	if output, err := cmd.CombinedOutput(); err != nil {
+		t.Fatalf("generated code does not compile: %v\n%s", err, output)
+	}
+}
+
+func TestGenerate_DisabledTransports(t *testing.T) {
+	svc, err := parseService("testdata/todo", "Service")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	files, err := generate(svc, config{Prefix: "todo."})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if want, have := 1, len(files); want != have {
+		t.Fatalf("unexpected number of generated files\nexpected: %d\nactual:   %d", want, have)
+	}
+
+	if want, have := "service_endpoints.gen.go", files[0].Name; want != have {
+		t.Errorf("unexpected file name\nexpected: %s\nactual:   %s", want, have)
+	}
+}
+
+func TestParseService_Errors(t *testing.T) {
+	tests := map[string]struct {
+		source string
+		err    string
+	}{
+		"not found": {
+			source: "type Other interface{}",
+			err:    "not found",
+		},
+		"not an interface": {
+			source: "type Service struct{}",
+			err:    "not an interface",
+		},
+		"missing context": {
+			source: "type Service interface{ Do(id string) error }",
+			err:    "context.Context",
+		},
+		"missing error": {
+			source: `import "context"
+
+type Service interface{ Do(ctx context.Context) string }`,
+			err: "error",
+		},
+		"unnamed parameter": {
+			source: `import "context"
+
+type Service interface{ Do(context.Context, string) error }`,
+			err: "named",
+		},
+	}
+
+	for name, test := range tests {
+		test := test
+
+		t.Run(name, func(t *testing.T) {
+			dir := t.TempDir()
+
+			source := "package test\n\n" + test.source + "\n"
+
+			if err := os.WriteFile(filepath.Join(dir, "service.go"), []byte(source), 0o600); err != nil {
+				t.Fatal(err)
+			}
+
+			_, err := parseService(dir, "Service")
+			if err == nil {
+				t.Fatal("expected an error")
+			}
+
+			if !strings.Contains(err.Error(), test.err) {
+				t.Errorf("unexpected error\nexpected: %s\nactual:   %s", test.err, err)
+			}
+		})
+	}
+}
+
+func TestSnakeCase(t *testing.T) {
+	tests := map[string]string{
+		"Service":     "service",
+		"TodoService": "todo_service",
+		"HTTPService": "http_service",
+	}
+
+	for name, want := range tests {
+		if have := snakeCase(name); want != have {
+			t.Errorf("unexpected snake case for %s\nexpected: %s\nactual:   %s", name, want, have)
+		}
+	}
+}
+
+func TestGuessPackageName(t *testing.T) {
+	tests := map[string]string{
+		"fmt":                               "fmt",
+		"github.com/nats-io/nats.go":        "nats",
+		"github.com/rabbitmq/amqp091-go":    "amqp091",
+		"github.com/go-kit/kit/v2/endpoint": "endpoint",
+		"github.com/jackc/pgx/v5":           "pgx",
+		"gopkg.in/yaml.v3":                  "yaml",
+		"github.com/mattn/go-sqlite3":       "sqlite3",
+	}
+
+	for path, want := range tests {
+		if have := guessPackageName(path); want != have {
+			t.Errorf("unexpected package name for %s\nexpected: %s\nactual:   %s", path, want, have)
+		}
+	}
+}
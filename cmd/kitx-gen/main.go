@@ -0,0 +1,74 @@
+// kitx-gen generates request/response structs, endpoints and transport stubs from a Go service interface.
+//
+// Usage:
+//
+//	kitx-gen -type Service [-prefix todo.] [-http] [-grpc] [dir]
+//
+// The generated files are written to the directory of the interface (the current directory by default).
+// It is usually invoked using go:generate:
+//
+//	//go:generate go run github.com/sagikazarmark/kitx/cmd/kitx-gen -type Service -http
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+func main() {
+	var (
+		typeName = flag.String("type", "", "name of the service interface (required)")
+		prefix   = flag.String("prefix", "", "operation name prefix (defaults to the package name followed by a dot)")
+		httpFlag = flag.Bool("http", false, "generate HTTP transport stubs")
+		grpcFlag = flag.Bool("grpc", false, "generate gRPC transport stubs")
+	)
+
+	flag.Usage = func() {
+		fmt.Fprintf(flag.CommandLine.Output(), "Usage: kitx-gen -type Service [flags] [dir]\n\nFlags:\n")
+		flag.PrintDefaults()
+	}
+
+	flag.Parse()
+
+	if *typeName == "" || flag.NArg() > 1 {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	dir := "."
+	if flag.NArg() == 1 {
+		dir = flag.Arg(0)
+	}
+
+	if err := run(dir, *typeName, *prefix, config{HTTP: *httpFlag, GRPC: *grpcFlag}); err != nil {
+		fmt.Fprintln(os.Stderr, "kitx-gen:", err)
+		os.Exit(1)
+	}
+}
+
+func run(dir string, typeName string, prefix string, c config) error {
+	svc, err := parseService(dir, typeName)
+	if err != nil {
+		return err
+	}
+
+	c.Prefix = prefix
+	if c.Prefix == "" {
+		c.Prefix = svc.Package + "."
+	}
+
+	files, err := generate(svc, c)
+	if err != nil {
+		return err
+	}
+
+	for _, file := range files {
+		if err := os.WriteFile(filepath.Join(dir, file.Name), file.Content, 0o644); err != nil { // nolint: gosec
+			return err
+		}
+	}
+
+	return nil
+}
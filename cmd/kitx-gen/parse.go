@@ -0,0 +1,339 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"go/ast"
+	"go/build"
+	"go/parser"
+	"go/printer"
+	"go/token"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// service is a parsed service interface.
+type service struct {
+	Package string
+	Name    string
+	Methods []method
+
+	// Imports are the imports of the source file referenced by method parameter and result types.
+	Imports []importSpec
+}
+
+type method struct {
+	Name    string
+	Params  []field
+	Results []field
+}
+
+type field struct {
+	// Name is the name of the parameter or result in the interface.
+	Name string
+
+	// FieldName is the name of the struct field.
+	FieldName string
+
+	Type     string
+	Variadic bool
+}
+
+type importSpec struct {
+	Name string
+	Path string
+}
+
+// parseService parses a service interface from the Go package in dir.
+// Generated files (*.gen.go) and test files are ignored.
+func parseService(dir string, name string) (service, error) {
+	fset := token.NewFileSet()
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return service{}, err
+	}
+
+	var filenames []string
+
+	for _, entry := range entries {
+		filename := entry.Name()
+
+		if entry.IsDir() ||
+			!strings.HasSuffix(filename, ".go") ||
+			strings.HasSuffix(filename, "_test.go") ||
+			strings.HasSuffix(filename, ".gen.go") {
+			continue
+		}
+
+		filenames = append(filenames, filename)
+	}
+
+	sort.Strings(filenames)
+
+	for _, filename := range filenames {
+		file, err := parser.ParseFile(fset, filepath.Join(dir, filename), nil, parser.SkipObjectResolution)
+		if err != nil {
+			return service{}, err
+		}
+
+		typeSpec := findType(file, name)
+		if typeSpec == nil {
+			continue
+		}
+
+		iface, ok := typeSpec.Type.(*ast.InterfaceType)
+		if !ok {
+			return service{}, fmt.Errorf("%s is not an interface", name)
+		}
+
+		return newService(fset, dir, file, name, iface)
+	}
+
+	return service{}, fmt.Errorf("type %s not found in %s", name, dir)
+}
+
+func findType(file *ast.File, name string) *ast.TypeSpec {
+	for _, decl := range file.Decls {
+		genDecl, ok := decl.(*ast.GenDecl)
+		if !ok || genDecl.Tok != token.TYPE {
+			continue
+		}
+
+		for _, spec := range genDecl.Specs {
+			if typeSpec := spec.(*ast.TypeSpec); typeSpec.Name.Name == name {
+				return typeSpec
+			}
+		}
+	}
+
+	return nil
+}
+
+func newService(fset *token.FileSet, dir string, file *ast.File, name string, iface *ast.InterfaceType) (service, error) {
+	svc := service{
+		Package: file.Name.Name,
+		Name:    name,
+	}
+
+	packages := map[string]bool{}
+
+	for _, m := range iface.Methods.List {
+		funcType, ok := m.Type.(*ast.FuncType)
+		if !ok {
+			return service{}, fmt.Errorf("%s: embedded interfaces are not supported", fset.Position(m.Pos()))
+		}
+
+		method, err := newMethod(fset, m.Names[0].Name, funcType)
+		if err != nil {
+			return service{}, err
+		}
+
+		ast.Inspect(funcType, func(node ast.Node) bool {
+			if sel, ok := node.(*ast.SelectorExpr); ok {
+				if ident, ok := sel.X.(*ast.Ident); ok {
+					packages[ident.Name] = true
+				}
+			}
+
+			return true
+		})
+
+		svc.Methods = append(svc.Methods, method)
+	}
+
+	for _, spec := range file.Imports {
+		path, _ := strconv.Unquote(spec.Path.Value)
+
+		var pkgName string
+		if spec.Name != nil {
+			pkgName = spec.Name.Name
+		} else {
+			pkgName = packageName(path, dir)
+		}
+
+		// context is always imported by generated code
+		if !packages[pkgName] || path == "context" {
+			continue
+		}
+
+		imp := importSpec{Path: path}
+		if spec.Name != nil {
+			imp.Name = spec.Name.Name
+		}
+
+		svc.Imports = append(svc.Imports, imp)
+	}
+
+	return svc, nil
+}
+
+// packageName returns the name of an imported package.
+//
+// The name is read from the package source (resolved from dir the same way the go command does).
+// If the package cannot be found, the name is guessed from the import path.
+func packageName(path string, dir string) string {
+	if pkg, err := build.Import(path, dir, 0); err == nil && pkg.Name != "" {
+		return pkg.Name
+	}
+
+	return guessPackageName(path)
+}
+
+// guessPackageName guesses the name of a package from its import path following common conventions
+// (eg. "gopkg.in/yaml.v3" is "yaml", "github.com/nats-io/nats.go" is "nats", "example.com/lib/v2" is "lib").
+func guessPackageName(path string) string {
+	elems := strings.Split(path, "/")
+	name := elems[len(elems)-1]
+
+	// Major version suffix
+	if len(elems) > 1 && isMajorVersion(name) {
+		name = elems[len(elems)-2]
+	}
+
+	// gopkg.in version suffix
+	if i := strings.LastIndex(name, ".v"); i > 0 && isMajorVersion(name[i+1:]) {
+		name = name[:i]
+	}
+
+	name = strings.TrimSuffix(name, ".go")
+	name = strings.TrimPrefix(name, "go-")
+	name = strings.TrimSuffix(name, "-go")
+
+	return strings.Map(func(r rune) rune {
+		if r == '_' || unicode.IsLetter(r) || unicode.IsDigit(r) {
+			return r
+		}
+
+		return -1
+	}, name)
+}
+
+func isMajorVersion(s string) bool {
+	if len(s) < 2 || s[0] != 'v' {
+		return false
+	}
+
+	_, err := strconv.Atoi(s[1:])
+
+	return err == nil
+}
+
+func newMethod(fset *token.FileSet, name string, funcType *ast.FuncType) (method, error) {
+	m := method{Name: name}
+
+	params := expandFields(funcType.Params)
+	if len(params) == 0 || exprString(fset, params[0].typ) != "context.Context" {
+		return method{}, fmt.Errorf("%s: the first parameter of %s must be a context.Context", fset.Position(funcType.Pos()), name)
+	}
+
+	for i, param := range params[1:] {
+		if param.name == "" || param.name == "_" {
+			return method{}, fmt.Errorf("%s: parameter %d of %s must be named", fset.Position(funcType.Pos()), i+1, name)
+		}
+
+		f := field{
+			Name:      param.name,
+			FieldName: exportedName(param.name),
+			Type:      exprString(fset, param.typ),
+		}
+
+		if ellipsis, ok := param.typ.(*ast.Ellipsis); ok {
+			f.Variadic = true
+			f.Type = "[]" + exprString(fset, ellipsis.Elt)
+		}
+
+		m.Params = append(m.Params, f)
+	}
+
+	results := expandFields(funcType.Results)
+	if len(results) == 0 || exprString(fset, results[len(results)-1].typ) != "error" {
+		return method{}, fmt.Errorf("%s: the last result of %s must be an error", fset.Position(funcType.Pos()), name)
+	}
+
+	results = results[:len(results)-1]
+
+	for i, result := range results {
+		resultName := result.name
+
+		if resultName == "" || resultName == "_" {
+			resultName = "result"
+
+			if len(results) > 1 {
+				resultName += strconv.Itoa(i)
+			}
+		}
+
+		m.Results = append(m.Results, field{
+			Name:      resultName,
+			FieldName: exportedName(resultName),
+			Type:      exprString(fset, result.typ),
+		})
+	}
+
+	return m, nil
+}
+
+type namedExpr struct {
+	name string
+	typ  ast.Expr
+}
+
+// expandFields returns one entry per name (eg. "a, b int" becomes two entries).
+func expandFields(fields *ast.FieldList) []namedExpr {
+	if fields == nil {
+		return nil
+	}
+
+	var exprs []namedExpr
+
+	for _, f := range fields.List {
+		if len(f.Names) == 0 {
+			exprs = append(exprs, namedExpr{typ: f.Type})
+
+			continue
+		}
+
+		for _, name := range f.Names {
+			exprs = append(exprs, namedExpr{name: name.Name, typ: f.Type})
+		}
+	}
+
+	return exprs
+}
+
+func exprString(fset *token.FileSet, expr ast.Expr) string {
+	var buf bytes.Buffer
+
+	_ = printer.Fprint(&buf, fset, expr)
+
+	return buf.String()
+}
+
+// commonInitialisms is a list of initialisms written in upper case in exported names.
+var commonInitialisms = map[string]bool{
+	"api": true, "id": true, "ids": true, "ip": true, "json": true, "http": true, "sql": true,
+	"uid": true, "uri": true, "url": true, "uuid": true, "xml": true,
+}
+
+// exportedName turns a parameter name into an exported struct field name (eg. "userID" becomes "UserID").
+func exportedName(name string) string {
+	i := strings.IndexFunc(name, unicode.IsUpper)
+	if i < 0 {
+		i = len(name)
+	}
+
+	if prefix := name[:i]; commonInitialisms[prefix] {
+		if prefix == "ids" {
+			return "IDs" + name[i:]
+		}
+
+		return strings.ToUpper(prefix) + name[i:]
+	}
+
+	return strings.ToUpper(name[:1]) + name[1:]
+}
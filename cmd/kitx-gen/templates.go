@@ -0,0 +1,199 @@
+package main
+
+import (
+	"text/template"
+)
+
+var funcs = template.FuncMap{
+	"isStdlib": isStdlib,
+}
+
+const header = `// Code generated by kitx-gen. DO NOT EDIT.
+
+package {{ .Package }}
+`
+
+var endpointsTemplate = template.Must(template.New("endpoints").Funcs(funcs).Parse(header + `
+import (
+	"context"
+	{{- range .Imports }}{{ if isStdlib .Path }}
+	{{ .Name }} "{{ .Path }}"{{ end }}{{ end }}
+
+	"github.com/go-kit/kit/endpoint"
+	{{- range .Imports }}{{ if not (isStdlib .Path) }}
+	{{ .Name }} "{{ .Path }}"{{ end }}{{ end }}
+
+	kitxendpoint "github.com/sagikazarmark/kitx/endpoint"
+)
+{{ range .Methods }}
+// {{ .Name }}Request is a request struct for {{ $.Name }}.{{ .Name }}.
+type {{ .Name }}Request struct{{ if .Params }} {
+	{{- range .Params }}
+	{{ .FieldName }} {{ .Type }} ` + "`" + `json:"{{ .Name }}"` + "`" + `
+	{{- end }}
+}{{ else }}{}{{ end }}
+
+// {{ .Name }}Response is a response struct for {{ $.Name }}.{{ .Name }}.
+type {{ .Name }}Response struct {
+	{{- range .Results }}
+	{{ .FieldName }} {{ .Type }} ` + "`" + `json:"{{ .Name }}"` + "`" + `
+	{{- end }}
+	Err error ` + "`" + `json:"-"` + "`" + `
+}
+
+// Failed implements endpoint.Failer.
+func (r {{ .Name }}Response) Failed() error {
+	return r.Err
+}
+{{ end }}
+// {{ .Name }}Endpoints collects the endpoints of {{ .Name }}.
+type {{ .Name }}Endpoints struct {
+	{{- range .Methods }}
+	{{ .Name }} endpoint.Endpoint
+	{{- end }}
+}
+
+// Make{{ .Name }}Endpoints returns the endpoints of {{ .Name }} wrapped with the middleware configured in a factory.
+//
+// Errors matching errorMatcher are returned as failed responses (see endpoint.Failer).
+// A nil errorMatcher returns every error as is.
+func Make{{ .Name }}Endpoints(service {{ .Name }}, factory kitxendpoint.Factory, errorMatcher kitxendpoint.ErrorMatcher) {{ .Name }}Endpoints {
+	return {{ .Name }}Endpoints{
+		{{- range .Methods }}
+		{{ .Name }}: kitxendpoint.NewTypedEndpoint(factory, "{{ $.Prefix }}{{ .Name }}", Make{{ .Name }}Endpoint(service, errorMatcher)),
+		{{- end }}
+	}
+}
+{{ range .Methods }}
+// Make{{ .Name }}Endpoint returns an endpoint for {{ $.Name }}.{{ .Name }}.
+func Make{{ .Name }}Endpoint(service {{ $.Name }}, errorMatcher kitxendpoint.ErrorMatcher) kitxendpoint.Typed[{{ .Name }}Request, {{ .Name }}Response] {
+	return func(ctx context.Context, {{ if .Params }}req{{ else }}_{{ end }} {{ .Name }}Request) ({{ .Name }}Response, error) {
+		{{ range $i, $r := .Results }}r{{ $i }}, {{ end }}err := service.{{ .Name }}(ctx{{ range .Params }}, req.{{ .FieldName }}{{ if .Variadic }}...{{ end }}{{ end }})
+		if err != nil {
+			if errorMatcher != nil && errorMatcher(err) {
+				return {{ .Name }}Response{Err: err}, nil
+			}
+
+			return {{ .Name }}Response{}, err
+		}
+
+		return {{ .Name }}Response{
+			{{- range $i, $r := .Results }}
+			{{ .FieldName }}: r{{ $i }},
+			{{- end }}
+		}, nil
+	}
+}
+{{ end }}`))
+
+var httpTemplate = template.Must(template.New("http").Funcs(funcs).Parse(header + `
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+
+	kithttp "github.com/go-kit/kit/transport/http"
+
+	kitxhttp "github.com/sagikazarmark/kitx/transport/http"
+)
+
+// {{ .Name }}HTTPHandlers collects the HTTP handlers of {{ .Name }}.
+type {{ .Name }}HTTPHandlers struct {
+	{{- range .Methods }}
+	{{ .Name }} *kithttp.Server
+	{{- end }}
+}
+
+// Make{{ .Name }}HTTPHandlers returns the HTTP handlers of {{ .Name }}.
+//
+// Requests and responses are encoded in JSON format. Failed responses are encoded using errorEncoder.
+func Make{{ .Name }}HTTPHandlers(
+	endpoints {{ .Name }}Endpoints,
+	factory kitxhttp.ServerFactory,
+	errorEncoder kitxhttp.EncodeErrorResponseFunc,
+) {{ .Name }}HTTPHandlers {
+	return {{ .Name }}HTTPHandlers{
+		{{- range .Methods }}
+		{{ .Name }}: factory.NewServer(
+			endpoints.{{ .Name }},
+			Decode{{ .Name }}HTTPRequest,
+			kitxhttp.ErrorResponseEncoder(kitxhttp.JSONResponseEncoder, errorEncoder),
+		),
+		{{- end }}
+	}
+}
+{{ range .Methods }}
+// Decode{{ .Name }}HTTPRequest decodes a {{ .Name }}Request from a JSON request body.
+func Decode{{ .Name }}HTTPRequest(_ context.Context, r *http.Request) (interface{}, error) {
+	var req {{ .Name }}Request
+
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil && !errors.Is(err, io.EOF) {
+		return nil, err
+	}
+
+	return req, nil
+}
+{{ end }}`))
+
+var grpcTemplate = template.Must(template.New("grpc").Funcs(funcs).Parse(header + `
+import (
+	"context"
+	"fmt"
+
+	kitgrpc "github.com/go-kit/kit/transport/grpc"
+
+	kitxgrpc "github.com/sagikazarmark/kitx/transport/grpc"
+)
+
+// {{ .Name }}GRPCCodec converts between gRPC messages and the request and response structs of {{ .Name }}.
+type {{ .Name }}GRPCCodec interface {
+	{{- range .Methods }}
+	// Decode{{ .Name }}Request decodes a {{ .Name }}Request from a gRPC request message.
+	Decode{{ .Name }}Request(ctx context.Context, request interface{}) ({{ .Name }}Request, error)
+
+	// Encode{{ .Name }}Response encodes a {{ .Name }}Response into a gRPC response message.
+	Encode{{ .Name }}Response(ctx context.Context, response {{ .Name }}Response) (interface{}, error)
+	{{ end }}
+}
+
+// {{ .Name }}GRPCHandlers collects the gRPC handlers of {{ .Name }}.
+type {{ .Name }}GRPCHandlers struct {
+	{{- range .Methods }}
+	{{ .Name }} *kitgrpc.Server
+	{{- end }}
+}
+
+// Make{{ .Name }}GRPCHandlers returns the gRPC handlers of {{ .Name }}.
+//
+// Messages are converted using codec. Failed responses are encoded using errorEncoder.
+func Make{{ .Name }}GRPCHandlers(
+	endpoints {{ .Name }}Endpoints,
+	codec {{ .Name }}GRPCCodec,
+	factory kitxgrpc.ServerFactory,
+	errorEncoder kitxgrpc.EncodeErrorResponseFunc,
+) {{ .Name }}GRPCHandlers {
+	return {{ .Name }}GRPCHandlers{
+		{{- range .Methods }}
+		{{ .Name }}: factory.NewServer(
+			endpoints.{{ .Name }},
+			func(ctx context.Context, request interface{}) (interface{}, error) {
+				return codec.Decode{{ .Name }}Request(ctx, request)
+			},
+			kitxgrpc.ErrorResponseEncoder(
+				func(ctx context.Context, response interface{}) (interface{}, error) {
+					resp, ok := response.({{ .Name }}Response)
+					if !ok {
+						return nil, fmt.Errorf("unexpected {{ .Name }} response type %T", response)
+					}
+
+					return codec.Encode{{ .Name }}Response(ctx, resp)
+				},
+				errorEncoder,
+			),
+		),
+		{{- end }}
+	}
+}
+`))
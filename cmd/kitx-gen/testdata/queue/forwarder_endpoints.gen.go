@@ -0,0 +1,63 @@
+// Code generated by kitx-gen. DO NOT EDIT.
+
+package queue
+
+import (
+	"context"
+
+	"github.com/go-kit/kit/endpoint"
+	"github.com/nats-io/nats.go"
+	"github.com/rabbitmq/amqp091-go"
+
+	kitxendpoint "github.com/sagikazarmark/kitx/endpoint"
+)
+
+// ForwardNATSRequest is a request struct for Forwarder.ForwardNATS.
+type ForwardNATSRequest struct {
+	Msg      *nats.Msg `json:"msg"`
+	Exchange string    `json:"exchange"`
+}
+
+// ForwardNATSResponse is a response struct for Forwarder.ForwardNATS.
+type ForwardNATSResponse struct {
+	Result amqp091.Publishing `json:"result"`
+	Err    error              `json:"-"`
+}
+
+// Failed implements endpoint.Failer.
+func (r ForwardNATSResponse) Failed() error {
+	return r.Err
+}
+
+// ForwarderEndpoints collects the endpoints of Forwarder.
+type ForwarderEndpoints struct {
+	ForwardNATS endpoint.Endpoint
+}
+
+// MakeForwarderEndpoints returns the endpoints of Forwarder wrapped with the middleware configured in a factory.
+//
+// Errors matching errorMatcher are returned as failed responses (see endpoint.Failer).
+// A nil errorMatcher returns every error as is.
+func MakeForwarderEndpoints(service Forwarder, factory kitxendpoint.Factory, errorMatcher kitxendpoint.ErrorMatcher) ForwarderEndpoints {
+	return ForwarderEndpoints{
+		ForwardNATS: kitxendpoint.NewTypedEndpoint(factory, "queue.ForwardNATS", MakeForwardNATSEndpoint(service, errorMatcher)),
+	}
+}
+
+// MakeForwardNATSEndpoint returns an endpoint for Forwarder.ForwardNATS.
+func MakeForwardNATSEndpoint(service Forwarder, errorMatcher kitxendpoint.ErrorMatcher) kitxendpoint.Typed[ForwardNATSRequest, ForwardNATSResponse] {
+	return func(ctx context.Context, req ForwardNATSRequest) (ForwardNATSResponse, error) {
+		r0, err := service.ForwardNATS(ctx, req.Msg, req.Exchange)
+		if err != nil {
+			if errorMatcher != nil && errorMatcher(err) {
+				return ForwardNATSResponse{Err: err}, nil
+			}
+
+			return ForwardNATSResponse{}, err
+		}
+
+		return ForwardNATSResponse{
+			Result: r0,
+		}, nil
+	}
+}
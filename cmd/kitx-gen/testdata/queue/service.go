@@ -0,0 +1,19 @@
+// Package queue is a test fixture for kitx-gen.
+//
+// It imports packages whose names do not match the last element of their import path.
+package queue
+
+import (
+	"context"
+
+	"github.com/nats-io/nats.go"
+	"github.com/rabbitmq/amqp091-go"
+)
+
+//go:generate go run github.com/sagikazarmark/kitx/cmd/kitx-gen -type Forwarder
+
+// Forwarder forwards messages between brokers.
+type Forwarder interface {
+	// ForwardNATS forwards a NATS message to an AMQP exchange.
+	ForwardNATS(ctx context.Context, msg *nats.Msg, exchange string) (amqp091.Publishing, error)
+}
@@ -0,0 +1,32 @@
+// Package todo is a test fixture for kitx-gen.
+package todo
+
+import (
+	"context"
+	"time"
+)
+
+//go:generate go run github.com/sagikazarmark/kitx/cmd/kitx-gen -type Service -http -grpc
+
+// Item is a todo item.
+type Item struct {
+	ID        string    `json:"id"`
+	Text      string    `json:"text"`
+	Done      bool      `json:"done"`
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+// Service manages a todo list.
+type Service interface {
+	// CreateItem adds a new item to the list.
+	CreateItem(ctx context.Context, text string, dueAt *time.Time) (id string, err error)
+
+	// ListItems returns every item in the list.
+	ListItems(ctx context.Context) ([]Item, error)
+
+	// MarkAsDone marks items as done.
+	MarkAsDone(ctx context.Context, itemIDs ...string) error
+
+	// Stats returns item counts.
+	Stats(context.Context) (int, int, error)
+}
@@ -0,0 +1,128 @@
+// Code generated by kitx-gen. DO NOT EDIT.
+
+package todo
+
+import (
+	"context"
+	"fmt"
+
+	kitgrpc "github.com/go-kit/kit/transport/grpc"
+
+	kitxgrpc "github.com/sagikazarmark/kitx/transport/grpc"
+)
+
+// ServiceGRPCCodec converts between gRPC messages and the request and response structs of Service.
+type ServiceGRPCCodec interface {
+	// DecodeCreateItemRequest decodes a CreateItemRequest from a gRPC request message.
+	DecodeCreateItemRequest(ctx context.Context, request interface{}) (CreateItemRequest, error)
+
+	// EncodeCreateItemResponse encodes a CreateItemResponse into a gRPC response message.
+	EncodeCreateItemResponse(ctx context.Context, response CreateItemResponse) (interface{}, error)
+
+	// DecodeListItemsRequest decodes a ListItemsRequest from a gRPC request message.
+	DecodeListItemsRequest(ctx context.Context, request interface{}) (ListItemsRequest, error)
+
+	// EncodeListItemsResponse encodes a ListItemsResponse into a gRPC response message.
+	EncodeListItemsResponse(ctx context.Context, response ListItemsResponse) (interface{}, error)
+
+	// DecodeMarkAsDoneRequest decodes a MarkAsDoneRequest from a gRPC request message.
+	DecodeMarkAsDoneRequest(ctx context.Context, request interface{}) (MarkAsDoneRequest, error)
+
+	// EncodeMarkAsDoneResponse encodes a MarkAsDoneResponse into a gRPC response message.
+	EncodeMarkAsDoneResponse(ctx context.Context, response MarkAsDoneResponse) (interface{}, error)
+
+	// DecodeStatsRequest decodes a StatsRequest from a gRPC request message.
+	DecodeStatsRequest(ctx context.Context, request interface{}) (StatsRequest, error)
+
+	// EncodeStatsResponse encodes a StatsResponse into a gRPC response message.
+	EncodeStatsResponse(ctx context.Context, response StatsResponse) (interface{}, error)
+}
+
+// ServiceGRPCHandlers collects the gRPC handlers of Service.
+type ServiceGRPCHandlers struct {
+	CreateItem *kitgrpc.Server
+	ListItems  *kitgrpc.Server
+	MarkAsDone *kitgrpc.Server
+	Stats      *kitgrpc.Server
+}
+
+// MakeServiceGRPCHandlers returns the gRPC handlers of Service.
+//
+// Messages are converted using codec. Failed responses are encoded using errorEncoder.
+func MakeServiceGRPCHandlers(
+	endpoints ServiceEndpoints,
+	codec ServiceGRPCCodec,
+	factory kitxgrpc.ServerFactory,
+	errorEncoder kitxgrpc.EncodeErrorResponseFunc,
+) ServiceGRPCHandlers {
+	return ServiceGRPCHandlers{
+		CreateItem: factory.NewServer(
+			endpoints.CreateItem,
+			func(ctx context.Context, request interface{}) (interface{}, error) {
+				return codec.DecodeCreateItemRequest(ctx, request)
+			},
+			kitxgrpc.ErrorResponseEncoder(
+				func(ctx context.Context, response interface{}) (interface{}, error) {
+					resp, ok := response.(CreateItemResponse)
+					if !ok {
+						return nil, fmt.Errorf("unexpected CreateItem response type %T", response)
+					}
+
+					return codec.EncodeCreateItemResponse(ctx, resp)
+				},
+				errorEncoder,
+			),
+		),
+		ListItems: factory.NewServer(
+			endpoints.ListItems,
+			func(ctx context.Context, request interface{}) (interface{}, error) {
+				return codec.DecodeListItemsRequest(ctx, request)
+			},
+			kitxgrpc.ErrorResponseEncoder(
+				func(ctx context.Context, response interface{}) (interface{}, error) {
+					resp, ok := response.(ListItemsResponse)
+					if !ok {
+						return nil, fmt.Errorf("unexpected ListItems response type %T", response)
+					}
+
+					return codec.EncodeListItemsResponse(ctx, resp)
+				},
+				errorEncoder,
+			),
+		),
+		MarkAsDone: factory.NewServer(
+			endpoints.MarkAsDone,
+			func(ctx context.Context, request interface{}) (interface{}, error) {
+				return codec.DecodeMarkAsDoneRequest(ctx, request)
+			},
+			kitxgrpc.ErrorResponseEncoder(
+				func(ctx context.Context, response interface{}) (interface{}, error) {
+					resp, ok := response.(MarkAsDoneResponse)
+					if !ok {
+						return nil, fmt.Errorf("unexpected MarkAsDone response type %T", response)
+					}
+
+					return codec.EncodeMarkAsDoneResponse(ctx, resp)
+				},
+				errorEncoder,
+			),
+		),
+		Stats: factory.NewServer(
+			endpoints.Stats,
+			func(ctx context.Context, request interface{}) (interface{}, error) {
+				return codec.DecodeStatsRequest(ctx, request)
+			},
+			kitxgrpc.ErrorResponseEncoder(
+				func(ctx context.Context, response interface{}) (interface{}, error) {
+					resp, ok := response.(StatsResponse)
+					if !ok {
+						return nil, fmt.Errorf("unexpected Stats response type %T", response)
+					}
+
+					return codec.EncodeStatsResponse(ctx, resp)
+				},
+				errorEncoder,
+			),
+		),
+	}
+}
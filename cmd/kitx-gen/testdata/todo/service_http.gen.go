@@ -0,0 +1,99 @@
+// Code generated by kitx-gen. DO NOT EDIT.
+
+package todo
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+
+	kithttp "github.com/go-kit/kit/transport/http"
+
+	kitxhttp "github.com/sagikazarmark/kitx/transport/http"
+)
+
+// ServiceHTTPHandlers collects the HTTP handlers of Service.
+type ServiceHTTPHandlers struct {
+	CreateItem *kithttp.Server
+	ListItems  *kithttp.Server
+	MarkAsDone *kithttp.Server
+	Stats      *kithttp.Server
+}
+
+// MakeServiceHTTPHandlers returns the HTTP handlers of Service.
+//
+// Requests and responses are encoded in JSON format. Failed responses are encoded using errorEncoder.
+func MakeServiceHTTPHandlers(
+	endpoints ServiceEndpoints,
+	factory kitxhttp.ServerFactory,
+	errorEncoder kitxhttp.EncodeErrorResponseFunc,
+) ServiceHTTPHandlers {
+	return ServiceHTTPHandlers{
+		CreateItem: factory.NewServer(
+			endpoints.CreateItem,
+			DecodeCreateItemHTTPRequest,
+			kitxhttp.ErrorResponseEncoder(kitxhttp.JSONResponseEncoder, errorEncoder),
+		),
+		ListItems: factory.NewServer(
+			endpoints.ListItems,
+			DecodeListItemsHTTPRequest,
+			kitxhttp.ErrorResponseEncoder(kitxhttp.JSONResponseEncoder, errorEncoder),
+		),
+		MarkAsDone: factory.NewServer(
+			endpoints.MarkAsDone,
+			DecodeMarkAsDoneHTTPRequest,
+			kitxhttp.ErrorResponseEncoder(kitxhttp.JSONResponseEncoder, errorEncoder),
+		),
+		Stats: factory.NewServer(
+			endpoints.Stats,
+			DecodeStatsHTTPRequest,
+			kitxhttp.ErrorResponseEncoder(kitxhttp.JSONResponseEncoder, errorEncoder),
+		),
+	}
+}
+
+// DecodeCreateItemHTTPRequest decodes a CreateItemRequest from a JSON request body.
+func DecodeCreateItemHTTPRequest(_ context.Context, r *http.Request) (interface{}, error) {
+	var req CreateItemRequest
+
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil && !errors.Is(err, io.EOF) {
+		return nil, err
+	}
+
+	return req, nil
+}
+
+// DecodeListItemsHTTPRequest decodes a ListItemsRequest from a JSON request body.
+func DecodeListItemsHTTPRequest(_ context.Context, r *http.Request) (interface{}, error) {
+	var req ListItemsRequest
+
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil && !errors.Is(err, io.EOF) {
+		return nil, err
+	}
+
+	return req, nil
+}
+
+// DecodeMarkAsDoneHTTPRequest decodes a MarkAsDoneRequest from a JSON request body.
+func DecodeMarkAsDoneHTTPRequest(_ context.Context, r *http.Request) (interface{}, error) {
+	var req MarkAsDoneRequest
+
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil && !errors.Is(err, io.EOF) {
+		return nil, err
+	}
+
+	return req, nil
+}
+
+// DecodeStatsHTTPRequest decodes a StatsRequest from a JSON request body.
+func DecodeStatsHTTPRequest(_ context.Context, r *http.Request) (interface{}, error) {
+	var req StatsRequest
+
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil && !errors.Is(err, io.EOF) {
+		return nil, err
+	}
+
+	return req, nil
+}
@@ -0,0 +1,100 @@
+package correlation
+
+import (
+	"context"
+
+	"github.com/go-kit/kit/transport/amqp"
+	amqp091 "github.com/rabbitmq/amqp091-go"
+)
+
+// AMQPToContext moves a correlation ID from the delivery headers table to context (if any).
+// It is designed to be used in AMQP subscribers.
+func AMQPToContext(headers ...string) amqp.RequestFunc {
+	if len(headers) == 0 {
+		headers = []string{defaultCorrelationHeader}
+	}
+
+	return func(ctx context.Context, _ *amqp091.Publishing, d *amqp091.Delivery) context.Context {
+		if d == nil {
+			return ctx
+		}
+
+		for _, header := range headers {
+			if cid := tableString(d.Headers, header); cid != "" {
+				return context.WithValue(ctx, correlationIDContextKey, cid)
+			}
+		}
+
+		return ctx
+	}
+}
+
+// AMQPPropertyToContext moves a correlation ID from the CorrelationId property of the delivery to context (if any).
+// It is designed to be used in AMQP subscribers.
+//
+// Note: go-kit's AMQP Publisher sets the CorrelationId property to a random value to match replies to requests,
+// so it should only be used with publishers that set the property to an actual correlation ID.
+func AMQPPropertyToContext() amqp.RequestFunc {
+	return func(ctx context.Context, _ *amqp091.Publishing, d *amqp091.Delivery) context.Context {
+		if d == nil || d.CorrelationId == "" {
+			return ctx
+		}
+
+		return context.WithValue(ctx, correlationIDContextKey, d.CorrelationId)
+	}
+}
+
+// AMQPFromContext moves a correlation ID from context to the publishing headers table (if any).
+// It is designed to be used in AMQP publishers.
+//
+// When multiple headers are given, the correlation ID is set in all of them.
+func AMQPFromContext(headers ...string) amqp.RequestFunc {
+	if len(headers) == 0 {
+		headers = []string{defaultCorrelationHeader}
+	}
+
+	return func(ctx context.Context, pub *amqp091.Publishing, _ *amqp091.Delivery) context.Context {
+		cid, ok := FromContext(ctx)
+		if !ok || cid == "" {
+			return ctx
+		}
+
+		if pub.Headers == nil {
+			pub.Headers = amqp091.Table{}
+		}
+
+		for _, header := range headers {
+			pub.Headers[header] = cid
+		}
+
+		return ctx
+	}
+}
+
+// AMQPPropertyFromContext moves a correlation ID from context to the CorrelationId property of the publishing (if any).
+// It is designed to be used in AMQP publishers.
+//
+// Note: go-kit's AMQP Publisher uses the CorrelationId property to match replies to requests
+// (see amqp.DefaultDeliverer), so it should only be used for fire-and-forget messages (see amqp.SendAndForgetDeliverer).
+func AMQPPropertyFromContext() amqp.RequestFunc {
+	return func(ctx context.Context, pub *amqp091.Publishing, _ *amqp091.Delivery) context.Context {
+		if cid, ok := FromContext(ctx); ok && cid != "" {
+			pub.CorrelationId = cid
+		}
+
+		return ctx
+	}
+}
+
+func tableString(table amqp091.Table, key string) string {
+	switch value := table[key].(type) {
+	case string:
+		return value
+
+	case []byte:
+		return string(value)
+
+	default:
+		return ""
+	}
+}
@@ -0,0 +1,144 @@
+package correlation
+
+import (
+	"context"
+	"testing"
+
+	"github.com/go-kit/kit/transport/amqp"
+	amqp091 "github.com/rabbitmq/amqp091-go"
+)
+
+// channelStub is an in-memory amqp.Channel that records published messages.
+type channelStub struct {
+	published []amqp091.Publishing
+}
+
+func (c *channelStub) Publish(_, _ string, _, _ bool, msg amqp091.Publishing) error {
+	c.published = append(c.published, msg)
+
+	return nil
+}
+
+func (c *channelStub) Consume(_, _ string, _, _, _, _ bool, _ amqp091.Table) (<-chan amqp091.Delivery, error) {
+	return make(chan amqp091.Delivery), nil
+}
+
+func TestAMQPToContext(t *testing.T) {
+	t.Run("default_header", func(t *testing.T) {
+		d := &amqp091.Delivery{Headers: amqp091.Table{"correlation-id": "1234"}}
+
+		ctx := AMQPToContext()(context.Background(), &amqp091.Publishing{}, d)
+
+		if cid, _ := FromContext(ctx); cid != "1234" {
+			t.Errorf("unexpected correlation ID\nexpected: %s\nactual:   %s", "1234", cid)
+		}
+	})
+
+	t.Run("custom_header", func(t *testing.T) {
+		d := &amqp091.Delivery{Headers: amqp091.Table{"x-correlation-id": []byte("1234")}}
+
+		ctx := AMQPToContext("correlation-id", "x-correlation-id")(context.Background(), &amqp091.Publishing{}, d)
+
+		if cid, _ := FromContext(ctx); cid != "1234" {
+			t.Errorf("unexpected correlation ID\nexpected: %s\nactual:   %s", "1234", cid)
+		}
+	})
+
+	t.Run("no_header", func(t *testing.T) {
+		d := &amqp091.Delivery{CorrelationId: "1234"}
+
+		ctx := AMQPToContext()(context.Background(), &amqp091.Publishing{}, d)
+
+		if _, ok := FromContext(ctx); ok {
+			t.Error("context should not contain the encoded correlation ID")
+		}
+	})
+}
+
+func TestAMQPPropertyToContext(t *testing.T) {
+	d := &amqp091.Delivery{CorrelationId: "1234"}
+
+	ctx := AMQPPropertyToContext()(context.Background(), &amqp091.Publishing{}, d)
+
+	if cid, _ := FromContext(ctx); cid != "1234" {
+		t.Errorf("unexpected correlation ID\nexpected: %s\nactual:   %s", "1234", cid)
+	}
+}
+
+func TestAMQPFromContext(t *testing.T) {
+	pub := &amqp091.Publishing{}
+
+	_ = AMQPFromContext()(ToContext(context.Background(), "1234"), pub, nil)
+
+	if want, have := "1234", tableString(pub.Headers, "correlation-id"); want != have {
+		t.Errorf("unexpected correlation ID header\nexpected: %s\nactual:   %s", want, have)
+	}
+
+	pub = &amqp091.Publishing{}
+
+	_ = AMQPFromContext()(context.Background(), pub, nil)
+
+	if len(pub.Headers) > 0 {
+		t.Error("publishing should not contain any headers")
+	}
+}
+
+func TestAMQPPropertyFromContext(t *testing.T) {
+	pub := &amqp091.Publishing{}
+
+	_ = AMQPPropertyFromContext()(ToContext(context.Background(), "1234"), pub, nil)
+
+	if want, have := "1234", pub.CorrelationId; want != have {
+		t.Errorf("unexpected correlation ID property\nexpected: %s\nactual:   %s", want, have)
+	}
+}
+
+func TestAMQPSubscriber(t *testing.T) {
+	var cid string
+
+	subscriber := amqp.NewSubscriber(
+		func(ctx context.Context, _ interface{}) (interface{}, error) {
+			cid, _ = FromContext(ctx)
+
+			return nil, nil
+		},
+		func(context.Context, *amqp091.Delivery) (interface{}, error) { return nil, nil },
+		amqp.EncodeNopResponse,
+		amqp.SubscriberBefore(AMQPToContext()),
+		amqp.SubscriberResponsePublisher(amqp.NopResponsePublisher),
+	)
+
+	ch := &channelStub{}
+
+	subscriber.ServeDelivery(ch)(&amqp091.Delivery{Headers: amqp091.Table{"correlation-id": "1234"}})
+
+	if want, have := "1234", cid; want != have {
+		t.Errorf("unexpected correlation ID\nexpected: %s\nactual:   %s", want, have)
+	}
+}
+
+func TestAMQPPublisher(t *testing.T) {
+	ch := &channelStub{}
+
+	publisher := amqp.NewPublisher(
+		ch,
+		&amqp091.Queue{Name: "reply"},
+		func(context.Context, *amqp091.Publishing, interface{}) error { return nil },
+		func(context.Context, *amqp091.Delivery) (interface{}, error) { return nil, nil },
+		amqp.PublisherBefore(AMQPFromContext()),
+		amqp.PublisherDeliverer(amqp.SendAndForgetDeliverer),
+	)
+
+	_, err := publisher.Endpoint()(ToContext(context.Background(), "1234"), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(ch.published) != 1 {
+		t.Fatalf("unexpected number of published messages\nexpected: %d\nactual:   %d", 1, len(ch.published))
+	}
+
+	if want, have := "1234", tableString(ch.published[0].Headers, "correlation-id"); want != have {
+		t.Errorf("unexpected correlation ID header\nexpected: %s\nactual:   %s", want, have)
+	}
+}
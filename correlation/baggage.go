@@ -0,0 +1,277 @@
+package correlation
+
+import (
+	"context"
+	stdhttp "net/http"
+	"net/url"
+	"sort"
+	"strings"
+
+	"github.com/go-kit/kit/transport/grpc"
+	"github.com/go-kit/kit/transport/http"
+	"google.golang.org/grpc/metadata"
+)
+
+// W3C Baggage header.
+//
+// See details at https://www.w3.org/TR/baggage/
+const baggageHeader = "baggage"
+
+// Default baggage limits.
+//
+// See details at https://www.w3.org/TR/baggage/#limits
+const (
+	defaultBaggageMaxMembers = 64
+	defaultBaggageMaxBytes   = 8192
+)
+
+// Baggage is a set of key/value pairs propagated across services alongside the correlation ID.
+//
+// Baggage member properties (metadata) are not supported: they are dropped during parsing.
+type Baggage map[string]string
+
+// baggageContextKey holds the key used to store baggage in the context.
+const baggageContextKey contextKey = "Baggage"
+
+// BaggageFromContext returns a copy of the baggage from the context (if any).
+// Returns false as the second parameter if none is found.
+func BaggageFromContext(ctx context.Context) (Baggage, bool) {
+	b, ok := ctx.Value(baggageContextKey).(Baggage)
+	if !ok {
+		return nil, false
+	}
+
+	return b.clone(), true
+}
+
+// BaggageToContext returns a new context annotated with baggage.
+// The baggage is copied, so later changes do not affect the context.
+func BaggageToContext(ctx context.Context, b Baggage) context.Context {
+	return context.WithValue(ctx, baggageContextKey, b.clone())
+}
+
+// BaggageValue returns a single baggage value from the context (if any).
+// Returns false as the second parameter if none is found.
+func BaggageValue(ctx context.Context, key string) (string, bool) {
+	b, _ := ctx.Value(baggageContextKey).(Baggage)
+
+	value, ok := b[key]
+
+	return value, ok
+}
+
+func (b Baggage) clone() Baggage {
+	c := make(Baggage, len(b))
+
+	for key, value := range b {
+		c[key] = value
+	}
+
+	return c
+}
+
+// BaggagePolicy describes the limits applied to baggage (both inbound and outbound).
+type BaggagePolicy struct {
+	// MaxMembers is the maximum number of baggage members.
+	// Defaults to 64.
+	MaxMembers int
+
+	// MaxBytes is the maximum size of the encoded baggage header.
+	// Defaults to 8192.
+	MaxBytes int
+
+	// AllowedKeys is the list of keys that are propagated.
+	// Empty means every key is allowed.
+	AllowedKeys []string
+}
+
+func (p BaggagePolicy) maxMembers() int {
+	if p.MaxMembers > 0 {
+		return p.MaxMembers
+	}
+
+	return defaultBaggageMaxMembers
+}
+
+func (p BaggagePolicy) maxBytes() int {
+	if p.MaxBytes > 0 {
+		return p.MaxBytes
+	}
+
+	return defaultBaggageMaxBytes
+}
+
+func (p BaggagePolicy) allowed(key string) bool {
+	if len(p.AllowedKeys) == 0 {
+		return true
+	}
+
+	for _, k := range p.AllowedKeys {
+		if k == key {
+			return true
+		}
+	}
+
+	return false
+}
+
+// ParseBaggage parses the value of a baggage header according to the policy.
+// Invalid members and members exceeding the limits are skipped.
+func (p BaggagePolicy) ParseBaggage(value string) Baggage {
+	b := Baggage{}
+	size := 0
+
+	for _, member := range strings.Split(value, ",") {
+		member = strings.TrimSpace(member)
+		if member == "" {
+			continue
+		}
+
+		// Stop parsing once the size limit is exceeded.
+		if size += len(member); size > p.maxBytes() {
+			break
+		}
+
+		// Drop properties
+		if i := strings.IndexByte(member, ';'); i >= 0 {
+			member = member[:i]
+		}
+
+		key, value, ok := strings.Cut(member, "=")
+		if !ok {
+			continue
+		}
+
+		key = strings.TrimSpace(key)
+		if !isBaggageKey(key) || !p.allowed(key) {
+			continue
+		}
+
+		value, err := url.PathUnescape(strings.TrimSpace(value))
+		if err != nil {
+			continue
+		}
+
+		if len(b) >= p.maxMembers() {
+			break
+		}
+
+		b[key] = value
+	}
+
+	return b
+}
+
+// EncodeBaggage encodes baggage as a baggage header value according to the policy.
+// Members exceeding the limits are skipped.
+func (p BaggagePolicy) EncodeBaggage(b Baggage) string {
+	keys := make([]string, 0, len(b))
+
+	for key := range b {
+		if isBaggageKey(key) && p.allowed(key) {
+			keys = append(keys, key)
+		}
+	}
+
+	// Make the output deterministic
+	sort.Strings(keys)
+
+	var sb strings.Builder
+
+	for i, key := range keys {
+		if i >= p.maxMembers() {
+			break
+		}
+
+		member := key + "=" + url.PathEscape(b[key])
+
+		size := sb.Len() + len(member)
+		if sb.Len() > 0 {
+			size++
+		}
+
+		if size > p.maxBytes() {
+			continue
+		}
+
+		if sb.Len() > 0 {
+			sb.WriteByte(',')
+		}
+
+		sb.WriteString(member)
+	}
+
+	return sb.String()
+}
+
+// isBaggageKey checks if a key is a valid RFC 7230 token.
+func isBaggageKey(key string) bool {
+	if key == "" {
+		return false
+	}
+
+	for i := 0; i < len(key); i++ {
+		c := key[i]
+
+		if c <= ' ' || c >= 0x7f || strings.IndexByte(`"(),/:;<=>?@[\]{}`, c) >= 0 {
+			return false
+		}
+	}
+
+	return true
+}
+
+func baggageToContext(ctx context.Context, policy BaggagePolicy, value string) context.Context {
+	if value == "" {
+		return ctx
+	}
+
+	b := policy.ParseBaggage(value)
+	if len(b) == 0 {
+		return ctx
+	}
+
+	return context.WithValue(ctx, baggageContextKey, b)
+}
+
+// HTTPBaggageToContext moves baggage from request header to context (if any).
+func HTTPBaggageToContext(policy BaggagePolicy) http.RequestFunc {
+	return func(ctx context.Context, r *stdhttp.Request) context.Context {
+		return baggageToContext(ctx, policy, strings.Join(r.Header.Values(baggageHeader), ","))
+	}
+}
+
+// GRPCBaggageToContext moves baggage from request metadata to context (if any).
+func GRPCBaggageToContext(policy BaggagePolicy) grpc.ServerRequestFunc {
+	return func(ctx context.Context, md metadata.MD) context.Context {
+		return baggageToContext(ctx, policy, strings.Join(md.Get(baggageHeader), ","))
+	}
+}
+
+// HTTPBaggageFromContext moves baggage from context to request header (if any).
+// It is designed to be used in HTTP clients.
+func HTTPBaggageFromContext(policy BaggagePolicy) http.RequestFunc {
+	return func(ctx context.Context, r *stdhttp.Request) context.Context {
+		b, _ := ctx.Value(baggageContextKey).(Baggage)
+
+		if value := policy.EncodeBaggage(b); value != "" {
+			r.Header.Set(baggageHeader, value)
+		}
+
+		return ctx
+	}
+}
+
+// GRPCBaggageFromContext moves baggage from context to request metadata (if any).
+// It is designed to be used in gRPC clients.
+func GRPCBaggageFromContext(policy BaggagePolicy) grpc.ClientRequestFunc {
+	return func(ctx context.Context, md *metadata.MD) context.Context {
+		b, _ := ctx.Value(baggageContextKey).(Baggage)
+
+		if value := policy.EncodeBaggage(b); value != "" {
+			md.Set(baggageHeader, value)
+		}
+
+		return ctx
+	}
+}
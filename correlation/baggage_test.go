@@ -0,0 +1,186 @@
+package correlation
+
+import (
+	"context"
+	"net/http"
+	"reflect"
+	"strings"
+	"testing"
+
+	"google.golang.org/grpc/metadata"
+)
+
+func TestBaggageToContext(t *testing.T) {
+	b := Baggage{"tenant": "acme"}
+
+	ctx := BaggageToContext(context.Background(), b)
+
+	// Changing the original value should not affect the context
+	b["tenant"] = "other"
+
+	value, ok := BaggageValue(ctx, "tenant")
+	if !ok {
+		t.Fatal("baggage value not found in the context")
+	}
+
+	if want, have := "acme", value; want != have {
+		t.Errorf("unexpected baggage value\nexpected: %s\nactual:   %s", want, have)
+	}
+
+	b2, ok := BaggageFromContext(ctx)
+	if !ok {
+		t.Fatal("baggage not found in the context")
+	}
+
+	if want, have := (Baggage{"tenant": "acme"}), b2; !reflect.DeepEqual(want, have) {
+		t.Errorf("unexpected baggage\nexpected: %v\nactual:   %v", want, have)
+	}
+}
+
+func TestBaggagePolicy_ParseBaggage(t *testing.T) {
+	tests := []struct {
+		name    string
+		policy  BaggagePolicy
+		value   string
+		baggage Baggage
+	}{
+		{
+			name:    "simple",
+			value:   "tenant=acme,caller=billing",
+			baggage: Baggage{"tenant": "acme", "caller": "billing"},
+		},
+		{
+			name:    "whitespace_and_properties",
+			value:   " tenant = acme ;prop=1 , bucket=b%20c",
+			baggage: Baggage{"tenant": "acme", "bucket": "b c"},
+		},
+		{
+			name:    "invalid_members",
+			value:   "tenant,=acme,bucket=%zz,caller=billing",
+			baggage: Baggage{"caller": "billing"},
+		},
+		{
+			name:    "allowed_keys",
+			policy:  BaggagePolicy{AllowedKeys: []string{"tenant"}},
+			value:   "tenant=acme,caller=billing",
+			baggage: Baggage{"tenant": "acme"},
+		},
+		{
+			name:    "max_members",
+			policy:  BaggagePolicy{MaxMembers: 1},
+			value:   "tenant=acme,caller=billing",
+			baggage: Baggage{"tenant": "acme"},
+		},
+		{
+			name:    "max_bytes",
+			policy:  BaggagePolicy{MaxBytes: 15},
+			value:   "tenant=acme,caller=billing",
+			baggage: Baggage{"tenant": "acme"},
+		},
+	}
+
+	for _, test := range tests {
+		test := test
+
+		t.Run(test.name, func(t *testing.T) {
+			if want, have := test.baggage, test.policy.ParseBaggage(test.value); !reflect.DeepEqual(want, have) {
+				t.Errorf("unexpected baggage\nexpected: %v\nactual:   %v", want, have)
+			}
+		})
+	}
+}
+
+func TestBaggagePolicy_EncodeBaggage(t *testing.T) {
+	tests := []struct {
+		name    string
+		policy  BaggagePolicy
+		baggage Baggage
+		value   string
+	}{
+		{
+			name:    "simple",
+			baggage: Baggage{"tenant": "acme", "caller": "billing service"},
+			value:   "caller=billing%20service,tenant=acme",
+		},
+		{
+			name:    "allowed_keys",
+			policy:  BaggagePolicy{AllowedKeys: []string{"tenant"}},
+			baggage: Baggage{"tenant": "acme", "caller": "billing"},
+			value:   "tenant=acme",
+		},
+		{
+			name:    "max_bytes",
+			policy:  BaggagePolicy{MaxBytes: 16},
+			baggage: Baggage{"tenant": "acme", "caller": strings.Repeat("a", 20)},
+			value:   "tenant=acme",
+		},
+		{
+			name:    "invalid_key",
+			baggage: Baggage{"ten ant": "acme"},
+			value:   "",
+		},
+	}
+
+	for _, test := range tests {
+		test := test
+
+		t.Run(test.name, func(t *testing.T) {
+			if want, have := test.value, test.policy.EncodeBaggage(test.baggage); want != have {
+				t.Errorf("unexpected baggage header\nexpected: %s\nactual:   %s", want, have)
+			}
+		})
+	}
+}
+
+func TestHTTPBaggage(t *testing.T) {
+	policy := BaggagePolicy{AllowedKeys: []string{"tenant", "caller"}}
+
+	header := http.Header{}
+	header.Add("baggage", "tenant=acme,secret=value")
+	header.Add("baggage", "caller=billing")
+
+	ctx := HTTPBaggageToContext(policy)(context.Background(), &http.Request{Header: header})
+
+	b, ok := BaggageFromContext(ctx)
+	if !ok {
+		t.Fatal("baggage not found in the context")
+	}
+
+	if want, have := (Baggage{"tenant": "acme", "caller": "billing"}), b; !reflect.DeepEqual(want, have) {
+		t.Errorf("unexpected baggage\nexpected: %v\nactual:   %v", want, have)
+	}
+
+	req := &http.Request{Header: http.Header{}}
+
+	_ = HTTPBaggageFromContext(policy)(ctx, req)
+
+	if want, have := "caller=billing,tenant=acme", req.Header.Get("baggage"); want != have {
+		t.Errorf("unexpected baggage header\nexpected: %s\nactual:   %s", want, have)
+	}
+}
+
+func TestGRPCBaggage(t *testing.T) {
+	policy := BaggagePolicy{}
+
+	ctx := GRPCBaggageToContext(policy)(context.Background(), metadata.Pairs("baggage", "tenant=acme"))
+
+	if value, _ := BaggageValue(ctx, "tenant"); value != "acme" {
+		t.Errorf("unexpected baggage value\nexpected: %s\nactual:   %s", "acme", value)
+	}
+
+	md := metadata.MD{}
+
+	_ = GRPCBaggageFromContext(policy)(ctx, &md)
+
+	if want, have := "tenant=acme", md.Get("baggage"); len(have) != 1 || want != have[0] {
+		t.Errorf("unexpected baggage header\nexpected: %s\nactual:   %v", want, have)
+	}
+
+	md = metadata.MD{}
+
+	_ = GRPCBaggageFromContext(policy)(context.Background(), &md)
+
+	if md.Len() > 0 {
+		t.Error("metadata should not contain any headers")
+	}
+}
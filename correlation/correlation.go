@@ -12,6 +12,44 @@ type contextKey string
 // correlation ID in the context.
 const correlationIDContextKey contextKey = "CorrelationID"
 
+// requestIDContextKey holds the key used to store a request ID in the context.
+const requestIDContextKey contextKey = "RequestID"
+
+// causationIDContextKey holds the key used to store a causation ID in the context.
+const causationIDContextKey contextKey = "CausationID"
+
+// correlationIDHolderContextKey holds the key used to store a correlation ID holder in the context.
+// The holder makes a correlation ID generated later in the request lifecycle (eg. by Middleware)
+// available to transport level response functions.
+const correlationIDHolderContextKey contextKey = "CorrelationIDHolder"
+
+type correlationIDHolder struct {
+	id string
+}
+
+func withHolder(ctx context.Context) context.Context {
+	return context.WithValue(ctx, correlationIDHolderContextKey, &correlationIDHolder{})
+}
+
+func setHolder(ctx context.Context, id string) {
+	if holder, ok := ctx.Value(correlationIDHolderContextKey).(*correlationIDHolder); ok {
+		holder.id = id
+	}
+}
+
+// fromContextOrHolder returns the correlation ID from the context or from the holder (if any).
+func fromContextOrHolder(ctx context.Context) (string, bool) {
+	if id, ok := FromContext(ctx); ok && id != "" {
+		return id, true
+	}
+
+	if holder, ok := ctx.Value(correlationIDHolderContextKey).(*correlationIDHolder); ok && holder.id != "" {
+		return holder.id, true
+	}
+
+	return "", false
+}
+
 // FromContext returns the correlation ID from the context (if any).
 // Returns false as the second parameter if none is found.
 func FromContext(ctx context.Context) (string, bool) {
@@ -24,3 +62,33 @@ func FromContext(ctx context.Context) (string, bool) {
 func ToContext(ctx context.Context, id string) context.Context {
 	return context.WithValue(ctx, correlationIDContextKey, id)
 }
+
+// RequestIDFromContext returns the request ID from the context (if any).
+// Returns false as the second parameter if none is found.
+//
+// Unlike the correlation ID (which identifies the whole flow), a request ID is unique to each hop.
+func RequestIDFromContext(ctx context.Context) (string, bool) {
+	id, ok := ctx.Value(requestIDContextKey).(string)
+
+	return id, ok
+}
+
+// RequestIDToContext returns a new context annotated with a request ID.
+func RequestIDToContext(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, requestIDContextKey, id)
+}
+
+// CausationIDFromContext returns the causation ID from the context (if any).
+// Returns false as the second parameter if none is found.
+//
+// The causation ID is the ID of the request (or message) that triggered the current one.
+func CausationIDFromContext(ctx context.Context) (string, bool) {
+	id, ok := ctx.Value(causationIDContextKey).(string)
+
+	return id, ok
+}
+
+// CausationIDToContext returns a new context annotated with a causation ID.
+func CausationIDToContext(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, causationIDContextKey, id)
+}
@@ -30,3 +30,33 @@ func TestToContext(t *testing.T) {
 		t.Errorf("unexpected correlation ID\nexpected: %s\nactual:   %s", want, have)
 	}
 }
+
+func TestRequestIDToContext(t *testing.T) {
+	ctx := RequestIDToContext(context.Background(), "id")
+
+	id, ok := RequestIDFromContext(ctx)
+	if !ok {
+		t.Fatal("request ID not found in the context")
+	}
+
+	if want, have := "id", id; want != have {
+		t.Errorf("unexpected request ID\nexpected: %s\nactual:   %s", want, have)
+	}
+
+	if _, ok := FromContext(ctx); ok {
+		t.Error("request ID is not supposed to be a correlation ID")
+	}
+}
+
+func TestCausationIDToContext(t *testing.T) {
+	ctx := CausationIDToContext(context.Background(), "id")
+
+	id, ok := CausationIDFromContext(ctx)
+	if !ok {
+		t.Fatal("causation ID not found in the context")
+	}
+
+	if want, have := "id", id; want != have {
+		t.Errorf("unexpected causation ID\nexpected: %s\nactual:   %s", want, have)
+	}
+}
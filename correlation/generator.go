@@ -0,0 +1,139 @@
+package correlation
+
+import (
+	"crypto/rand"
+	"encoding/binary"
+	"encoding/hex"
+	"time"
+)
+
+// Generator generates new correlation IDs.
+//
+// Implementations MUST be safe for concurrent use.
+type Generator interface {
+	// Generate returns a new correlation ID.
+	Generate() string
+}
+
+// GeneratorFunc is an adapter to allow the use of ordinary functions as a Generator.
+type GeneratorFunc func() string
+
+// Generate calls fn().
+func (fn GeneratorFunc) Generate() string {
+	return fn()
+}
+
+// NewUUIDv4Generator returns a Generator that generates random (version 4) UUIDs.
+//
+// See details at https://www.rfc-editor.org/rfc/rfc9562#section-5.4
+func NewUUIDv4Generator() Generator {
+	return GeneratorFunc(func() string {
+		var u [16]byte
+
+		randomBytes(u[:])
+
+		u[6] = (u[6] & 0x0f) | 0x40 // version 4
+		u[8] = (u[8] & 0x3f) | 0x80 // variant 10
+
+		return formatUUID(u)
+	})
+}
+
+// NewUUIDv7Generator returns a Generator that generates time-ordered (version 7) UUIDs.
+//
+// See details at https://www.rfc-editor.org/rfc/rfc9562#section-5.7
+func NewUUIDv7Generator() Generator {
+	return GeneratorFunc(func() string {
+		var u [16]byte
+
+		randomBytes(u[6:])
+		putTimestamp(u[:6], time.Now())
+
+		u[6] = (u[6] & 0x0f) | 0x70 // version 7
+		u[8] = (u[8] & 0x3f) | 0x80 // variant 10
+
+		return formatUUID(u)
+	})
+}
+
+// NewULIDGenerator returns a Generator that generates ULIDs.
+//
+// See details at https://github.com/ulid/spec
+func NewULIDGenerator() Generator {
+	return GeneratorFunc(func() string {
+		var u [16]byte
+
+		randomBytes(u[6:])
+		putTimestamp(u[:6], time.Now())
+
+		return formatULID(u)
+	})
+}
+
+// NewRandomGenerator returns a Generator that generates cryptographically secure random alphanumeric strings
+// of the given length.
+func NewRandomGenerator(length int) Generator {
+	return GeneratorFunc(func() string {
+		b := make([]byte, length)
+
+		randomBytes(b)
+
+		for i := range b {
+			// 256 is not divisible by len(charset), but the bias is negligible for correlation IDs.
+			b[i] = charset[int(b[i])%len(charset)]
+		}
+
+		return string(b)
+	})
+}
+
+func randomBytes(b []byte) {
+	if _, err := rand.Read(b); err != nil {
+		panic("correlation: failed to read random bytes: " + err.Error())
+	}
+}
+
+// putTimestamp writes the Unix timestamp in milliseconds as a 48-bit big-endian integer.
+func putTimestamp(b []byte, t time.Time) {
+	var ts [8]byte
+
+	binary.BigEndian.PutUint64(ts[:], uint64(t.UnixMilli())) // nolint: gosec
+
+	copy(b, ts[2:])
+}
+
+func formatUUID(u [16]byte) string {
+	var buf [36]byte
+
+	hex.Encode(buf[0:8], u[0:4])
+	buf[8] = '-'
+	hex.Encode(buf[9:13], u[4:6])
+	buf[13] = '-'
+	hex.Encode(buf[14:18], u[6:8])
+	buf[18] = '-'
+	hex.Encode(buf[19:23], u[8:10])
+	buf[23] = '-'
+	hex.Encode(buf[24:], u[10:])
+
+	return string(buf[:])
+}
+
+const crockfordAlphabet = "0123456789ABCDEFGHJKMNPQRSTVWXYZ"
+
+// formatULID encodes 128 bits as 26 Crockford's Base32 characters.
+func formatULID(u [16]byte) string {
+	hi := binary.BigEndian.Uint64(u[:8])
+	lo := binary.BigEndian.Uint64(u[8:])
+
+	var buf [26]byte
+
+	// The first character encodes the top 3 bits only (26 * 5 = 130 bits).
+	for i := 25; i >= 0; i-- {
+		buf[i] = crockfordAlphabet[lo&0x1f]
+
+		lo = (lo >> 5) | (hi << 59)
+		hi >>= 5
+	}
+
+	return string(buf[:])
+}
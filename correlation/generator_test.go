@@ -0,0 +1,132 @@
+package correlation
+
+import (
+	"regexp"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestGenerators(t *testing.T) {
+	tests := []struct {
+		name      string
+		generator Generator
+		pattern   *regexp.Regexp
+	}{
+		{
+			name:      "uuidv4",
+			generator: NewUUIDv4Generator(),
+			pattern:   regexp.MustCompile(`^[0-9a-f]{8}-[0-9a-f]{4}-4[0-9a-f]{3}-[89ab][0-9a-f]{3}-[0-9a-f]{12}$`),
+		},
+		{
+			name:      "uuidv7",
+			generator: NewUUIDv7Generator(),
+			pattern:   regexp.MustCompile(`^[0-9a-f]{8}-[0-9a-f]{4}-7[0-9a-f]{3}-[89ab][0-9a-f]{3}-[0-9a-f]{12}$`),
+		},
+		{
+			name:      "ulid",
+			generator: NewULIDGenerator(),
+			pattern:   regexp.MustCompile(`^[0-7][0-9A-HJKMNP-TV-Z]{25}$`),
+		},
+		{
+			name:      "random",
+			generator: NewRandomGenerator(32),
+			pattern:   regexp.MustCompile(`^[a-zA-Z0-9]{32}$`),
+		},
+	}
+
+	for _, test := range tests {
+		test := test
+
+		t.Run(test.name, func(t *testing.T) {
+			const n = 1000
+
+			var mu sync.Mutex
+			var wg sync.WaitGroup
+
+			ids := make(map[string]struct{}, n)
+
+			for i := 0; i < n; i++ {
+				wg.Add(1)
+
+				go func() {
+					defer wg.Done()
+
+					id := test.generator.Generate()
+
+					mu.Lock()
+					ids[id] = struct{}{}
+					mu.Unlock()
+				}()
+			}
+
+			wg.Wait()
+
+			if want, have := n, len(ids); want != have {
+				t.Errorf("generated IDs are supposed to be unique\nexpected: %d\nactual:   %d", want, have)
+			}
+
+			for id := range ids {
+				if !test.pattern.MatchString(id) {
+					t.Fatalf("generated ID does not match the expected format\nexpected: %s\nactual:   %s", test.pattern, id)
+				}
+			}
+		})
+	}
+}
+
+func TestUUIDv7Generator_TimeOrdered(t *testing.T) {
+	generator := NewUUIDv7Generator()
+
+	first := generator.Generate()
+
+	time.Sleep(2 * time.Millisecond)
+
+	second := generator.Generate()
+
+	if strings.Compare(first, second) >= 0 {
+		t.Errorf("UUIDs are supposed to be time-ordered\nfirst:  %s\nsecond: %s", first, second)
+	}
+}
+
+func TestULIDGenerator_Timestamp(t *testing.T) {
+	var u [16]byte
+
+	putTimestamp(u[:6], time.UnixMilli(1469918176385))
+
+	// Example from the ULID spec
+	if want, have := "01ARYZ6S41", formatULID(u)[:10]; want != have {
+		t.Errorf("unexpected ULID timestamp\nexpected: %s\nactual:   %s", want, have)
+	}
+}
+
+func benchmarkGenerator(b *testing.B, generator Generator) {
+	b.ReportAllocs()
+
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			_ = generator.Generate()
+		}
+	})
+}
+
+func BenchmarkUUIDv4Generator(b *testing.B) {
+	benchmarkGenerator(b, NewUUIDv4Generator())
+}
+
+func BenchmarkUUIDv7Generator(b *testing.B) {
+	benchmarkGenerator(b, NewUUIDv7Generator())
+}
+
+func BenchmarkULIDGenerator(b *testing.B) {
+	benchmarkGenerator(b, NewULIDGenerator())
+}
+
+func BenchmarkRandomGenerator(b *testing.B) {
+	benchmarkGenerator(b, NewRandomGenerator(32))
+}
+
+func BenchmarkDefaultGenerator(b *testing.B) {
+	benchmarkGenerator(b, GeneratorFunc(generate))
+}
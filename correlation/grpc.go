@@ -0,0 +1,109 @@
+package correlation
+
+import (
+	"context"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+// GRPCUnaryServerInterceptor returns a gRPC unary server interceptor that moves a correlation ID
+// from request metadata to context or generates a new one if none is found.
+//
+// It can be used with gRPC services that are not served by a go-kit gRPC server.
+func GRPCUnaryServerInterceptor(opts ...HandlerOption) grpc.UnaryServerInterceptor {
+	o := newHandlerOptions(opts)
+
+	return func(
+		ctx context.Context,
+		req interface{},
+		_ *grpc.UnaryServerInfo,
+		handler grpc.UnaryHandler,
+	) (interface{}, error) {
+		return handler(grpcServerContext(ctx, o), req)
+	}
+}
+
+// GRPCStreamServerInterceptor returns a gRPC stream server interceptor that moves a correlation ID
+// from request metadata to context or generates a new one if none is found.
+//
+// It can be used with gRPC services that are not served by a go-kit gRPC server.
+func GRPCStreamServerInterceptor(opts ...HandlerOption) grpc.StreamServerInterceptor {
+	o := newHandlerOptions(opts)
+
+	return func(srv interface{}, ss grpc.ServerStream, _ *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		return handler(srv, serverStream{ss, grpcServerContext(ss.Context(), o)})
+	}
+}
+
+// GRPCUnaryClientInterceptor returns a gRPC unary client interceptor that moves a correlation ID
+// from context to request metadata (if any).
+//
+// It can be used with gRPC clients that are not wrapped by a go-kit gRPC client.
+func GRPCUnaryClientInterceptor(opts ...HandlerOption) grpc.UnaryClientInterceptor {
+	o := newHandlerOptions(opts)
+
+	return func(
+		ctx context.Context,
+		method string,
+		req, reply interface{},
+		cc *grpc.ClientConn,
+		invoker grpc.UnaryInvoker,
+		opts ...grpc.CallOption,
+	) error {
+		return invoker(grpcClientContext(ctx, o.headers[0]), method, req, reply, cc, opts...)
+	}
+}
+
+// GRPCStreamClientInterceptor returns a gRPC stream client interceptor that moves a correlation ID
+// from context to request metadata (if any).
+//
+// It can be used with gRPC clients that are not wrapped by a go-kit gRPC client.
+func GRPCStreamClientInterceptor(opts ...HandlerOption) grpc.StreamClientInterceptor {
+	o := newHandlerOptions(opts)
+
+	return func(
+		ctx context.Context,
+		desc *grpc.StreamDesc,
+		cc *grpc.ClientConn,
+		method string,
+		streamer grpc.Streamer,
+		opts ...grpc.CallOption,
+	) (grpc.ClientStream, error) {
+		return streamer(grpcClientContext(ctx, o.headers[0]), desc, cc, method, opts...)
+	}
+}
+
+func grpcServerContext(ctx context.Context, o handlerOptions) context.Context {
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		md = metadata.MD{}
+	}
+
+	return ensureID(grpcToContext(o.policy, o.headers)(ctx, md), o.generator)
+}
+
+func grpcClientContext(ctx context.Context, header string) context.Context {
+	cid, ok := FromContext(ctx)
+	if !ok || cid == "" {
+		return ctx
+	}
+
+	// Do not override a correlation ID set explicitly
+	if md, ok := metadata.FromOutgoingContext(ctx); ok && len(md.Get(header)) > 0 {
+		return ctx
+	}
+
+	return metadata.AppendToOutgoingContext(ctx, header, cid)
+}
+
+// serverStream overrides the context of a grpc.ServerStream.
+type serverStream struct {
+	grpc.ServerStream
+
+	ctx context.Context
+}
+
+func (s serverStream) Context() context.Context {
+	return s.ctx
+}
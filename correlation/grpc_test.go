@@ -0,0 +1,168 @@
+package correlation
+
+import (
+	"context"
+	"testing"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+func TestGRPCUnaryServerInterceptor(t *testing.T) {
+	interceptor := GRPCUnaryServerInterceptor()
+
+	var cid string
+
+	handler := func(ctx context.Context, _ interface{}) (interface{}, error) {
+		cid, _ = FromContext(ctx)
+
+		return nil, nil
+	}
+
+	t.Run("existing", func(t *testing.T) {
+		ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs("correlation-id", "1234"))
+
+		_, _ = interceptor(ctx, nil, &grpc.UnaryServerInfo{}, handler)
+
+		if want, have := "1234", cid; want != have {
+			t.Errorf("unexpected correlation ID\nexpected: %s\nactual:   %s", want, have)
+		}
+	})
+
+	t.Run("generated", func(t *testing.T) {
+		cid = ""
+
+		_, _ = interceptor(context.Background(), nil, &grpc.UnaryServerInfo{}, handler)
+
+		if cid == "" {
+			t.Error("correlation ID is supposed to be generated")
+		}
+	})
+}
+
+type serverStreamStub struct {
+	grpc.ServerStream
+
+	ctx context.Context
+}
+
+func (s serverStreamStub) Context() context.Context {
+	return s.ctx
+}
+
+func TestGRPCStreamServerInterceptor(t *testing.T) {
+	var cid string
+
+	ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs("correlation-id", "1234"))
+
+	_ = GRPCStreamServerInterceptor()(
+		nil,
+		serverStreamStub{ctx: ctx},
+		&grpc.StreamServerInfo{},
+		func(_ interface{}, stream grpc.ServerStream) error {
+			cid, _ = FromContext(stream.Context())
+
+			return nil
+		},
+	)
+
+	if want, have := "1234", cid; want != have {
+		t.Errorf("unexpected correlation ID\nexpected: %s\nactual:   %s", want, have)
+	}
+}
+
+func TestGRPCUnaryClientInterceptor(t *testing.T) {
+	var md metadata.MD
+
+	invoker := func(ctx context.Context, _ string, _, _ interface{}, _ *grpc.ClientConn, _ ...grpc.CallOption) error {
+		md, _ = metadata.FromOutgoingContext(ctx)
+
+		return nil
+	}
+
+	_ = GRPCUnaryClientInterceptor()(ToContext(context.Background(), "1234"), "/test", nil, nil, nil, invoker)
+
+	if want, have := "1234", md.Get("correlation-id"); len(have) != 1 || want != have[0] {
+		t.Errorf("unexpected correlation ID header\nexpected: %s\nactual:   %v", want, have)
+	}
+}
+
+func TestGRPCStreamClientInterceptor(t *testing.T) {
+	var md metadata.MD
+
+	streamer := func(
+		ctx context.Context,
+		_ *grpc.StreamDesc,
+		_ *grpc.ClientConn,
+		_ string,
+		_ ...grpc.CallOption,
+	) (grpc.ClientStream, error) {
+		md, _ = metadata.FromOutgoingContext(ctx)
+
+		return nil, nil
+	}
+
+	ctx := metadata.AppendToOutgoingContext(ToContext(context.Background(), "1234"), "correlation-id", "explicit")
+
+	_, _ = GRPCStreamClientInterceptor()(ctx, &grpc.StreamDesc{}, nil, "/test", streamer)
+
+	if want, have := "explicit", md.Get("correlation-id"); len(have) != 1 || want != have[0] {
+		t.Errorf("unexpected correlation ID header\nexpected: %s\nactual:   %v", want, have)
+	}
+}
+
+func TestGRPCUnaryServerInterceptor_Options(t *testing.T) {
+	interceptor := GRPCUnaryServerInterceptor(
+		HandlerHeaders("x-correlation-id"),
+		HandlerGenerator(GeneratorFunc(func() string { return "generated" })),
+		HandlerValidationPolicy(ValidationPolicy{MaxLength: 4}),
+	)
+
+	var cid string
+
+	handler := func(ctx context.Context, _ interface{}) (interface{}, error) {
+		cid, _ = FromContext(ctx)
+
+		return nil, nil
+	}
+
+	tests := []struct {
+		name string
+		md   metadata.MD
+		cid  string
+	}{
+		{"custom header", metadata.Pairs("x-correlation-id", "1234"), "1234"},
+		{"default header", metadata.Pairs("correlation-id", "1234"), "generated"},
+		{"invalid", metadata.Pairs("x-correlation-id", "12345"), "generated"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			cid = ""
+
+			_, _ = interceptor(metadata.NewIncomingContext(context.Background(), test.md), nil, &grpc.UnaryServerInfo{}, handler)
+
+			if want, have := test.cid, cid; want != have {
+				t.Errorf("unexpected correlation ID\nexpected: %s\nactual:   %s", want, have)
+			}
+		})
+	}
+}
+
+func TestGRPCUnaryClientInterceptor_Headers(t *testing.T) {
+	var md metadata.MD
+
+	invoker := func(ctx context.Context, _ string, _, _ interface{}, _ *grpc.ClientConn, _ ...grpc.CallOption) error {
+		md, _ = metadata.FromOutgoingContext(ctx)
+
+		return nil
+	}
+
+	interceptor := GRPCUnaryClientInterceptor(HandlerHeaders("x-correlation-id"))
+
+	_ = interceptor(ToContext(context.Background(), "1234"), "/test", nil, nil, nil, invoker)
+
+	if want, have := "1234", md.Get("x-correlation-id"); len(have) != 1 || want != have[0] {
+		t.Errorf("unexpected correlation ID header\nexpected: %s\nactual:   %v", want, have)
+	}
+}
@@ -0,0 +1,138 @@
+package correlation
+
+import (
+	"context"
+	"net/http"
+)
+
+// HandlerOption configures correlation ID handling in net/http middleware and gRPC interceptors.
+type HandlerOption interface {
+	apply(o *handlerOptions)
+}
+
+type handlerOptions struct {
+	headers   []string
+	generator Generator
+	policy    *ValidationPolicy
+}
+
+type handlerOptionFunc func(o *handlerOptions)
+
+func (fn handlerOptionFunc) apply(o *handlerOptions) {
+	fn(o)
+}
+
+// HandlerHeaders sets the headers (or gRPC metadata keys) carrying the correlation ID.
+// Incoming requests are checked in order, outgoing requests use the first one.
+// gRPC metadata keys must be lowercase.
+//
+// By default, DefaultHeader is used.
+func HandlerHeaders(headers ...string) HandlerOption {
+	return handlerOptionFunc(func(o *handlerOptions) { o.headers = headers })
+}
+
+// HandlerGenerator sets the Generator used for new correlation IDs.
+//
+// By default, a 32 character long cryptographically secure random string is generated (see NewRandomGenerator).
+func HandlerGenerator(generator Generator) HandlerOption {
+	return handlerOptionFunc(func(o *handlerOptions) { o.generator = generator })
+}
+
+// HandlerValidationPolicy validates incoming correlation IDs using a policy (see ValidationPolicy).
+//
+// By default, incoming correlation IDs are not validated.
+func HandlerValidationPolicy(policy ValidationPolicy) HandlerOption {
+	return handlerOptionFunc(func(o *handlerOptions) { o.policy = &policy })
+}
+
+func newHandlerOptions(opts []HandlerOption) handlerOptions {
+	o := handlerOptions{
+		generator: NewRandomGenerator(32),
+	}
+
+	for _, opt := range opts {
+		opt.apply(&o)
+	}
+
+	if len(o.headers) == 0 {
+		o.headers = []string{defaultCorrelationHeader}
+	}
+
+	if o.generator == nil {
+		o.generator = NewRandomGenerator(32)
+	}
+
+	return o
+}
+
+// HTTPMiddleware is a net/http middleware that moves a correlation ID from request header to context
+// or generates a new one if none is found.
+//
+// It can be used with handlers that are not served by a go-kit HTTP server (eg. health checks or webhooks).
+// Use NewHTTPMiddleware to customize it.
+func HTTPMiddleware(next http.Handler) http.Handler {
+	return NewHTTPMiddleware()(next)
+}
+
+// NewHTTPMiddleware returns a net/http middleware that moves a correlation ID from request header to context
+// or generates a new one if none is found.
+//
+// It can be used with handlers that are not served by a go-kit HTTP server (eg. health checks or webhooks).
+func NewHTTPMiddleware(opts ...HandlerOption) func(http.Handler) http.Handler {
+	o := newHandlerOptions(opts)
+	toContext := httpToContext(o.policy, o.headers)
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			ctx := ensureID(toContext(r.Context(), r), o.generator)
+
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	}
+}
+
+// HTTPRoundTripper is an http.RoundTripper that moves a correlation ID from context to request header (if any).
+//
+// It can be used with HTTP clients that are not wrapped by a go-kit HTTP client.
+func HTTPRoundTripper(next http.RoundTripper) http.RoundTripper {
+	if next == nil {
+		next = http.DefaultTransport
+	}
+
+	return roundTripperFunc(func(r *http.Request) (*http.Response, error) {
+		cid, ok := FromContext(r.Context())
+		if !ok || cid == "" {
+			return next.RoundTrip(r)
+		}
+
+		// A RoundTripper should not modify the request
+		r = r.Clone(r.Context())
+		r.Header.Set(defaultCorrelationHeader, cid)
+
+		return next.RoundTrip(r)
+	})
+}
+
+type roundTripperFunc func(r *http.Request) (*http.Response, error)
+
+func (fn roundTripperFunc) RoundTrip(r *http.Request) (*http.Response, error) {
+	return fn(r)
+}
+
+// ensureID makes sure the context contains a correlation ID by generating one if necessary.
+// If generator is nil, the default generator is used.
+func ensureID(ctx context.Context, generator Generator) context.Context {
+	if cid, ok := FromContext(ctx); ok && cid != "" {
+		return ctx
+	}
+
+	if generator == nil {
+		generator = GeneratorFunc(generate)
+	}
+
+	cid := generator.Generate()
+
+	setHolder(ctx, cid)
+
+	return ToContext(ctx, cid)
+}
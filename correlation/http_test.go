@@ -0,0 +1,102 @@
+package correlation
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestHTTPMiddleware(t *testing.T) {
+	var cid string
+
+	handler := HTTPMiddleware(http.HandlerFunc(func(_ http.ResponseWriter, r *http.Request) {
+		cid, _ = FromContext(r.Context())
+	}))
+
+	t.Run("existing", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		req.Header.Set("Correlation-ID", "1234")
+
+		handler.ServeHTTP(httptest.NewRecorder(), req)
+
+		if want, have := "1234", cid; want != have {
+			t.Errorf("unexpected correlation ID\nexpected: %s\nactual:   %s", want, have)
+		}
+	})
+
+	t.Run("generated", func(t *testing.T) {
+		cid = ""
+
+		handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+
+		if cid == "" {
+			t.Error("correlation ID is supposed to be generated")
+		}
+	})
+}
+
+func TestHTTPRoundTripper(t *testing.T) {
+	var header string
+
+	server := httptest.NewServer(http.HandlerFunc(func(_ http.ResponseWriter, r *http.Request) {
+		header = r.Header.Get("Correlation-ID")
+	}))
+	defer server.Close()
+
+	client := &http.Client{Transport: HTTPRoundTripper(nil)}
+
+	req, _ := http.NewRequestWithContext(ToContext(context.Background(), "1234"), http.MethodGet, server.URL, nil)
+
+	resp, err := client.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+
+	if want, have := "1234", header; want != have {
+		t.Errorf("unexpected correlation ID header\nexpected: %s\nactual:   %s", want, have)
+	}
+
+	if req.Header.Get("Correlation-ID") != "" {
+		t.Error("original request is not supposed to be modified")
+	}
+}
+
+func TestNewHTTPMiddleware(t *testing.T) {
+	var cid string
+
+	handler := NewHTTPMiddleware(
+		HandlerHeaders("X-Correlation-ID"),
+		HandlerGenerator(GeneratorFunc(func() string { return "generated" })),
+		HandlerValidationPolicy(ValidationPolicy{MaxLength: 4}),
+	)(http.HandlerFunc(func(_ http.ResponseWriter, r *http.Request) {
+		cid, _ = FromContext(r.Context())
+	}))
+
+	tests := []struct {
+		name   string
+		header string
+		value  string
+		cid    string
+	}{
+		{"custom header", "X-Correlation-ID", "1234", "1234"},
+		{"default header", "Correlation-ID", "1234", "generated"},
+		{"invalid", "X-Correlation-ID", "12345\nforged", "generated"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			cid = ""
+
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			req.Header.Set(test.header, test.value)
+
+			handler.ServeHTTP(httptest.NewRecorder(), req)
+
+			if want, have := test.cid, cid; want != have {
+				t.Errorf("unexpected correlation ID\nexpected: %s\nactual:   %s", want, have)
+			}
+		})
+	}
+}
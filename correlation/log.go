@@ -0,0 +1,19 @@
+package correlation
+
+import (
+	"context"
+
+	"github.com/go-kit/log"
+)
+
+// Valuer returns a go-kit log.Valuer that returns the correlation ID from the context.
+// It returns an empty string if there is no correlation ID in the context.
+//
+//	logger = log.With(logger, "correlation_id", correlation.Valuer(ctx))
+func Valuer(ctx context.Context) log.Valuer {
+	return func() interface{} {
+		cid, _ := FromContext(ctx)
+
+		return cid
+	}
+}
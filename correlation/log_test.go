@@ -0,0 +1,23 @@
+package correlation
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/go-kit/log"
+)
+
+func TestValuer(t *testing.T) {
+	var buf bytes.Buffer
+
+	logger := log.NewLogfmtLogger(&buf)
+
+	_ = log.With(logger, "correlation_id", Valuer(ToContext(context.Background(), "1234"))).Log("msg", "hello")
+	_ = log.With(logger, "correlation_id", Valuer(context.Background())).Log("msg", "hello")
+
+	expected := "correlation_id=1234 msg=hello\ncorrelation_id= msg=hello\n"
+	if want, have := expected, buf.String(); want != have {
+		t.Errorf("unexpected log output\nexpected: %s\nactual:   %s", want, have)
+	}
+}
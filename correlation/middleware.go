@@ -19,13 +19,79 @@ func generate() string {
 	return string(b)
 }
 
+// MiddlewareOption configures a correlation ID middleware.
+type MiddlewareOption interface {
+	apply(o *middlewareOptions)
+}
+
+type middlewareOptions struct {
+	generator Generator
+}
+
+type middlewareOptionFunc func(o *middlewareOptions)
+
+func (fn middlewareOptionFunc) apply(o *middlewareOptions) {
+	fn(o)
+}
+
+// WithGenerator sets the Generator used for new correlation IDs.
+//
+// By default, a 32 character long string is generated using the global (math/rand) random number generator.
+func WithGenerator(generator Generator) MiddlewareOption {
+	return middlewareOptionFunc(func(o *middlewareOptions) { o.generator = generator })
+}
+
 // Middleware creates a new middleware that ensures the context contains a correlation ID.
-func Middleware() endpoint.Middleware {
+func Middleware(opts ...MiddlewareOption) endpoint.Middleware {
+	o := middlewareOptions{
+		generator: GeneratorFunc(generate),
+	}
+
+	for _, opt := range opts {
+		opt.apply(&o)
+	}
+
 	return func(next endpoint.Endpoint) endpoint.Endpoint {
 		return func(ctx context.Context, request interface{}) (response interface{}, err error) {
 			cid, ok := ctx.Value(correlationIDContextKey).(string)
 			if !ok || cid == "" {
-				ctx = context.WithValue(ctx, correlationIDContextKey, generate())
+				cid = o.generator.Generate()
+
+				ctx = context.WithValue(ctx, correlationIDContextKey, cid)
+				setHolder(ctx, cid)
+			}
+
+			return next(ctx, request)
+		}
+	}
+}
+
+// MiddlewareWithGenerator creates a new middleware that ensures the context contains a correlation ID.
+// New correlation IDs are generated using the given Generator.
+func MiddlewareWithGenerator(generator Generator) endpoint.Middleware {
+	return Middleware(WithGenerator(generator))
+}
+
+// RequestIDMiddleware creates a new middleware that generates a request ID for the current hop.
+//
+// A request ID already present in the context (eg. one assigned by a load balancer and extracted by
+// HTTPRequestIDToContext) is kept.
+//
+// The request ID is sent to downstream services as causation ID by HTTPCausationIDFromContext
+// and GRPCCausationIDFromContext.
+func RequestIDMiddleware(opts ...MiddlewareOption) endpoint.Middleware {
+	o := middlewareOptions{
+		generator: GeneratorFunc(generate),
+	}
+
+	for _, opt := range opts {
+		opt.apply(&o)
+	}
+
+	return func(next endpoint.Endpoint) endpoint.Endpoint {
+		return func(ctx context.Context, request interface{}) (response interface{}, err error) {
+			if rid, ok := RequestIDFromContext(ctx); !ok || rid == "" {
+				ctx = RequestIDToContext(ctx, o.generator.Generate())
 			}
 
 			return next(ctx, request)
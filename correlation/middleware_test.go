@@ -4,6 +4,8 @@ import (
 	"context"
 	"math/rand"
 	"testing"
+
+	"github.com/go-kit/kit/endpoint"
 )
 
 func TestMiddleware(t *testing.T) {
@@ -54,3 +56,71 @@ func TestMiddleware(t *testing.T) {
 		_, _ = e(ctx, nil)
 	})
 }
+
+func TestMiddlewareWithGenerator(t *testing.T) {
+	generator := GeneratorFunc(func() string { return "generated" })
+
+	for name, mw := range map[string]endpoint.Middleware{
+		"option":   Middleware(WithGenerator(generator)),
+		"shortcut": MiddlewareWithGenerator(generator),
+	} {
+		mw := mw
+
+		t.Run(name, func(t *testing.T) {
+			var cid string
+
+			e := mw(func(ctx context.Context, _ interface{}) (interface{}, error) {
+				cid, _ = FromContext(ctx)
+
+				return nil, nil
+			})
+
+			_, _ = e(context.Background(), nil)
+
+			if want, have := "generated", cid; want != have {
+				t.Errorf("unexpected correlation ID\nexpected: %s\nactual:   %s", want, have)
+			}
+		})
+	}
+}
+
+func TestRequestIDMiddleware(t *testing.T) {
+	generator := GeneratorFunc(func() string { return "generated" })
+
+	tests := []struct {
+		name      string
+		ctx       context.Context
+		requestID string
+	}{
+		{
+			name:      "existing",
+			ctx:       RequestIDToContext(context.Background(), "4321"),
+			requestID: "4321",
+		},
+		{
+			name:      "generated",
+			ctx:       context.Background(),
+			requestID: "generated",
+		},
+	}
+
+	for _, test := range tests {
+		test := test
+
+		t.Run(test.name, func(t *testing.T) {
+			var rid string
+
+			e := RequestIDMiddleware(WithGenerator(generator))(func(ctx context.Context, _ interface{}) (interface{}, error) {
+				rid, _ = RequestIDFromContext(ctx)
+
+				return nil, nil
+			})
+
+			_, _ = e(test.ctx, nil)
+
+			if want, have := test.requestID, rid; want != have {
+				t.Errorf("unexpected request ID\nexpected: %s\nactual:   %s", want, have)
+			}
+		})
+	}
+}
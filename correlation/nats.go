@@ -0,0 +1,58 @@
+package correlation
+
+import (
+	"context"
+
+	kitnats "github.com/go-kit/kit/transport/nats"
+	"github.com/nats-io/nats.go"
+)
+
+// NATSToContext moves a correlation ID from message header to context (if any).
+// It is designed to be used in NATS subscribers.
+//
+// Note: NATS headers are case-sensitive.
+func NATSToContext(headers ...string) kitnats.RequestFunc {
+	if len(headers) == 0 {
+		headers = []string{defaultCorrelationHeader}
+	}
+
+	return func(ctx context.Context, msg *nats.Msg) context.Context {
+		for _, header := range headers {
+			if cid := msg.Header.Get(header); cid != "" {
+				return context.WithValue(ctx, correlationIDContextKey, cid)
+			}
+		}
+
+		return ctx
+	}
+}
+
+// NATSFromContext moves a correlation ID from context to message header (if any).
+// It is designed to be used in NATS publishers.
+//
+// When multiple headers are given, the correlation ID is set in all of them.
+//
+// Note: go-kit's NATS Publisher (as of v0.13.0) only sends the message data, discarding headers.
+// Use the Publisher in kitx's transport/nats package instead, which sends the whole message.
+func NATSFromContext(headers ...string) kitnats.RequestFunc {
+	if len(headers) == 0 {
+		headers = []string{defaultCorrelationHeader}
+	}
+
+	return func(ctx context.Context, msg *nats.Msg) context.Context {
+		cid, ok := FromContext(ctx)
+		if !ok || cid == "" {
+			return ctx
+		}
+
+		if msg.Header == nil {
+			msg.Header = nats.Header{}
+		}
+
+		for _, header := range headers {
+			msg.Header.Set(header, cid)
+		}
+
+		return ctx
+	}
+}
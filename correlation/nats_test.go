@@ -0,0 +1,68 @@
+package correlation
+
+import (
+	"context"
+	"testing"
+
+	"github.com/nats-io/nats.go"
+)
+
+func TestNATSToContext(t *testing.T) {
+	t.Run("no_header", func(t *testing.T) {
+		ctx := NATSToContext()(context.Background(), &nats.Msg{})
+
+		if _, ok := FromContext(ctx); ok {
+			t.Error("context should not contain the encoded correlation ID")
+		}
+	})
+
+	t.Run("default_header", func(t *testing.T) {
+		msg := nats.NewMsg("subject")
+		msg.Header.Set("correlation-id", "1234")
+
+		ctx := NATSToContext()(context.Background(), msg)
+
+		if cid, _ := FromContext(ctx); cid != "1234" {
+			t.Errorf("unexpected correlation ID\nexpected: %s\nactual:   %s", "1234", cid)
+		}
+	})
+
+	t.Run("custom_header", func(t *testing.T) {
+		msg := nats.NewMsg("subject")
+		msg.Header.Set("X-Correlation-ID", "1234")
+
+		ctx := NATSToContext("correlation-id", "X-Correlation-ID")(context.Background(), msg)
+
+		if cid, _ := FromContext(ctx); cid != "1234" {
+			t.Errorf("unexpected correlation ID\nexpected: %s\nactual:   %s", "1234", cid)
+		}
+	})
+}
+
+func TestNATSFromContext(t *testing.T) {
+	msg := &nats.Msg{Subject: "subject"}
+
+	_ = NATSFromContext()(ToContext(context.Background(), "1234"), msg)
+
+	if want, have := "1234", msg.Header.Get("correlation-id"); want != have {
+		t.Errorf("unexpected correlation ID header\nexpected: %s\nactual:   %s", want, have)
+	}
+
+	msg = &nats.Msg{Subject: "subject"}
+
+	_ = NATSFromContext()(context.Background(), msg)
+
+	if msg.Header != nil {
+		t.Error("message should not contain any headers")
+	}
+
+	// Round trip
+	msg = &nats.Msg{Subject: "subject"}
+
+	_ = NATSFromContext()(ToContext(context.Background(), "4321"), msg)
+	ctx := NATSToContext()(context.Background(), msg)
+
+	if cid, _ := FromContext(ctx); cid != "4321" {
+		t.Errorf("unexpected correlation ID\nexpected: %s\nactual:   %s", "4321", cid)
+	}
+}
@@ -0,0 +1,240 @@
+package correlation
+
+import (
+	"context"
+	"encoding/hex"
+	stdhttp "net/http"
+	"strings"
+
+	"github.com/go-kit/kit/transport/grpc"
+	"github.com/go-kit/kit/transport/http"
+	"google.golang.org/grpc/metadata"
+)
+
+// W3C Trace Context headers.
+//
+// See details at https://www.w3.org/TR/trace-context/
+const (
+	traceparentHeader = "traceparent"
+	tracestateHeader  = "tracestate"
+)
+
+// TraceContext holds the parsed values of the W3C Trace Context headers.
+//
+// See details at https://www.w3.org/TR/trace-context/
+type TraceContext struct {
+	// TraceID is the ID of the whole trace (32 lowercase hex characters).
+	TraceID string
+
+	// ParentID is the ID of the caller's span (16 lowercase hex characters).
+	ParentID string
+
+	// Flags holds the trace flags (eg. sampled).
+	Flags byte
+
+	// State holds the raw value of the tracestate header (if any).
+	State string
+}
+
+// traceContextContextKey holds the key used to store a trace context in the context.
+const traceContextContextKey contextKey = "TraceContext"
+
+// TraceContextFromContext returns the trace context from the context (if any).
+// Returns false as the second parameter if none is found.
+func TraceContextFromContext(ctx context.Context) (TraceContext, bool) {
+	tc, ok := ctx.Value(traceContextContextKey).(TraceContext)
+
+	return tc, ok
+}
+
+// TraceContextToContext returns a new context annotated with a trace context.
+func TraceContextToContext(ctx context.Context, tc TraceContext) context.Context {
+	return context.WithValue(ctx, traceContextContextKey, tc)
+}
+
+// ParseTraceParent parses the value of a traceparent header.
+// Returns false as the second parameter if the value is invalid.
+func ParseTraceParent(value string) (TraceContext, bool) {
+	// version "-" trace-id "-" parent-id "-" trace-flags
+	const length = 2 + 1 + 32 + 1 + 16 + 1 + 2
+
+	if len(value) < length {
+		return TraceContext{}, false
+	}
+
+	version := value[0:2]
+	if !isLowerHex(version) || version == "ff" {
+		return TraceContext{}, false
+	}
+
+	// Future versions may append fields, but version 00 must match the exact length.
+	if len(value) > length && (version == "00" || value[length] != '-') {
+		return TraceContext{}, false
+	}
+
+	if value[2] != '-' || value[35] != '-' || value[52] != '-' {
+		return TraceContext{}, false
+	}
+
+	traceID := value[3:35]
+	parentID := value[36:52]
+	flags := value[53:55]
+
+	if !isLowerHex(traceID) || isZero(traceID) || !isLowerHex(parentID) || isZero(parentID) || !isLowerHex(flags) {
+		return TraceContext{}, false
+	}
+
+	var f [1]byte
+
+	_, _ = hex.Decode(f[:], []byte(flags))
+
+	return TraceContext{
+		TraceID:  traceID,
+		ParentID: parentID,
+		Flags:    f[0],
+	}, true
+}
+
+// TraceParent returns the traceparent header value (version 00) for an outgoing request
+// with the given span ID.
+func (tc TraceContext) TraceParent(spanID string) string {
+	return "00-" + tc.TraceID + "-" + spanID + "-" + hex.EncodeToString([]byte{tc.Flags})
+}
+
+func isLowerHex(s string) bool {
+	for i := 0; i < len(s); i++ {
+		if (s[i] < '0' || s[i] > '9') && (s[i] < 'a' || s[i] > 'f') {
+			return false
+		}
+	}
+
+	return true
+}
+
+func isZero(s string) bool {
+	return strings.Trim(s, "0") == ""
+}
+
+func traceContextToContext(ctx context.Context, traceparent string, tracestate string) context.Context {
+	tc, ok := ParseTraceParent(traceparent)
+	if !ok {
+		return ctx
+	}
+
+	tc.State = tracestate
+
+	ctx = TraceContextToContext(ctx, tc)
+
+	// The trace ID only becomes the correlation ID when no explicit correlation ID is present.
+	if cid, ok := FromContext(ctx); !ok || cid == "" {
+		ctx = context.WithValue(ctx, correlationIDContextKey, tc.TraceID)
+	}
+
+	return ctx
+}
+
+// HTTPTraceContextToContext moves a W3C trace context from request header to context (if any).
+//
+// The trace ID is used as the correlation ID when the context does not contain one yet.
+// To make sure an explicit correlation ID header takes precedence, use it together with HTTPToContext.
+func HTTPTraceContextToContext() http.RequestFunc {
+	return func(ctx context.Context, r *stdhttp.Request) context.Context {
+		return traceContextToContext(
+			ctx,
+			r.Header.Get(traceparentHeader),
+			strings.Join(r.Header.Values(tracestateHeader), ","),
+		)
+	}
+}
+
+// GRPCTraceContextToContext moves a W3C trace context from request metadata to context (if any).
+//
+// The trace ID is used as the correlation ID when the context does not contain one yet.
+// To make sure an explicit correlation ID header takes precedence, use it together with GRPCToContext.
+func GRPCTraceContextToContext() grpc.ServerRequestFunc {
+	return func(ctx context.Context, md metadata.MD) context.Context {
+		var traceparent, tracestate string
+
+		if values := md.Get(traceparentHeader); len(values) > 0 {
+			traceparent = values[0]
+		}
+
+		if values := md.Get(tracestateHeader); len(values) > 0 {
+			tracestate = strings.Join(values, ",")
+		}
+
+		return traceContextToContext(ctx, traceparent, tracestate)
+	}
+}
+
+// outgoingTraceContext returns the trace context for an outgoing request.
+//
+// The trace ID is taken from the trace context in the context (if any).
+// Otherwise, the correlation ID is used if it's a valid trace ID (or a UUID),
+// so that the trace can be correlated with the rest of the request flow.
+// As a last resort, a new random trace ID is generated.
+func outgoingTraceContext(ctx context.Context) TraceContext {
+	if tc, ok := TraceContextFromContext(ctx); ok {
+		return tc
+	}
+
+	if cid, ok := FromContext(ctx); ok {
+		traceID := strings.ToLower(strings.ReplaceAll(cid, "-", ""))
+		if len(traceID) == 32 && isLowerHex(traceID) && !isZero(traceID) {
+			return TraceContext{TraceID: traceID}
+		}
+	}
+
+	return TraceContext{TraceID: randomHex(16)}
+}
+
+func randomHex(n int) string {
+	b := make([]byte, n)
+
+	for {
+		randomBytes(b)
+
+		// All zero IDs are invalid.
+		for _, c := range b {
+			if c != 0 {
+				return hex.EncodeToString(b)
+			}
+		}
+	}
+}
+
+// HTTPTraceContextFromContext sets W3C trace context headers in the request.
+// It is designed to be used in HTTP clients.
+//
+// A new parent (span) ID is generated for every outgoing request.
+func HTTPTraceContextFromContext() http.RequestFunc {
+	return func(ctx context.Context, r *stdhttp.Request) context.Context {
+		tc := outgoingTraceContext(ctx)
+
+		r.Header.Set(traceparentHeader, tc.TraceParent(randomHex(8)))
+
+		if tc.State != "" {
+			r.Header.Set(tracestateHeader, tc.State)
+		}
+
+		return ctx
+	}
+}
+
+// GRPCTraceContextFromContext sets W3C trace context headers in the request metadata.
+// It is designed to be used in gRPC clients.
+//
+// A new parent (span) ID is generated for every outgoing request.
+func GRPCTraceContextFromContext() grpc.ClientRequestFunc {
+	return func(ctx context.Context, md *metadata.MD) context.Context {
+		tc := outgoingTraceContext(ctx)
+
+		md.Set(traceparentHeader, tc.TraceParent(randomHex(8)))
+
+		if tc.State != "" {
+			md.Set(tracestateHeader, tc.State)
+		}
+
+		return ctx
+	}
+}
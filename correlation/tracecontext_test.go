@@ -0,0 +1,209 @@
+package correlation
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"google.golang.org/grpc/metadata"
+)
+
+func TestParseTraceParent(t *testing.T) {
+	tests := []struct {
+		value string
+		valid bool
+	}{
+		{"00-0af7651916cd43dd8448eb211c80319c-b7ad6b7169203331-01", true},
+		{"01-0af7651916cd43dd8448eb211c80319c-b7ad6b7169203331-01-future", true},
+		{"00-0af7651916cd43dd8448eb211c80319c-b7ad6b7169203331-01-future", false},
+		{"ff-0af7651916cd43dd8448eb211c80319c-b7ad6b7169203331-01", false},
+		{"00-0AF7651916CD43DD8448EB211C80319C-b7ad6b7169203331-01", false},
+		{"00-00000000000000000000000000000000-b7ad6b7169203331-01", false},
+		{"00-0af7651916cd43dd8448eb211c80319c-0000000000000000-01", false},
+		{"00-0af7651916cd43dd8448eb211c80319c-b7ad6b7169203331", false},
+		{"00_0af7651916cd43dd8448eb211c80319c_b7ad6b7169203331_01", false},
+		{"", false},
+	}
+
+	for _, test := range tests {
+		tc, ok := ParseTraceParent(test.value)
+		if want, have := test.valid, ok; want != have {
+			t.Errorf("unexpected result for %q\nexpected: %t\nactual:   %t", test.value, want, have)
+
+			continue
+		}
+
+		if !ok {
+			continue
+		}
+
+		if want, have := "0af7651916cd43dd8448eb211c80319c", tc.TraceID; want != have {
+			t.Errorf("unexpected trace ID\nexpected: %s\nactual:   %s", want, have)
+		}
+
+		if want, have := "b7ad6b7169203331", tc.ParentID; want != have {
+			t.Errorf("unexpected parent ID\nexpected: %s\nactual:   %s", want, have)
+		}
+
+		if want, have := byte(1), tc.Flags; want != have {
+			t.Errorf("unexpected flags\nexpected: %d\nactual:   %d", want, have)
+		}
+	}
+}
+
+func TestHTTPTraceContextToContext(t *testing.T) {
+	const traceparent = "00-0af7651916cd43dd8448eb211c80319c-b7ad6b7169203331-01"
+
+	t.Run("trace_context", func(t *testing.T) {
+		header := http.Header{}
+		header.Set("traceparent", traceparent)
+		header.Set("tracestate", "congo=t61rcWkgMzE")
+
+		ctx := HTTPTraceContextToContext()(context.Background(), &http.Request{Header: header})
+
+		tc, ok := TraceContextFromContext(ctx)
+		if !ok {
+			t.Fatal("trace context not found in the context")
+		}
+
+		if want, have := "congo=t61rcWkgMzE", tc.State; want != have {
+			t.Errorf("unexpected trace state\nexpected: %s\nactual:   %s", want, have)
+		}
+
+		if want, have := "0af7651916cd43dd8448eb211c80319c", tc.TraceID; want != have {
+			t.Errorf("unexpected trace ID\nexpected: %s\nactual:   %s", want, have)
+		}
+
+		if cid, _ := FromContext(ctx); cid != tc.TraceID {
+			t.Errorf("unexpected correlation ID\nexpected: %s\nactual:   %s", tc.TraceID, cid)
+		}
+	})
+
+	t.Run("explicit_correlation_id", func(t *testing.T) {
+		header := http.Header{}
+		header.Set("traceparent", traceparent)
+		header.Set("correlation-id", "1234")
+
+		req := &http.Request{Header: header}
+
+		ctx := HTTPTraceContextToContext()(HTTPToContext()(context.Background(), req), req)
+
+		if cid, _ := FromContext(ctx); cid != "1234" {
+			t.Errorf("unexpected correlation ID\nexpected: %s\nactual:   %s", "1234", cid)
+		}
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		header := http.Header{}
+		header.Set("traceparent", "invalid")
+
+		ctx := HTTPTraceContextToContext()(context.Background(), &http.Request{Header: header})
+
+		if _, ok := TraceContextFromContext(ctx); ok {
+			t.Error("context should not contain a trace context")
+		}
+
+		if _, ok := FromContext(ctx); ok {
+			t.Error("context should not contain the encoded correlation ID")
+		}
+	})
+}
+
+func TestGRPCTraceContextToContext(t *testing.T) {
+	md := metadata.Pairs("traceparent", "00-0af7651916cd43dd8448eb211c80319c-b7ad6b7169203331-00")
+
+	ctx := GRPCTraceContextToContext()(context.Background(), md)
+
+	tc, ok := TraceContextFromContext(ctx)
+	if !ok {
+		t.Fatal("trace context not found in the context")
+	}
+
+	if want, have := "b7ad6b7169203331", tc.ParentID; want != have {
+		t.Errorf("unexpected parent ID\nexpected: %s\nactual:   %s", want, have)
+	}
+
+	if cid, _ := FromContext(ctx); cid != tc.TraceID {
+		t.Errorf("unexpected correlation ID\nexpected: %s\nactual:   %s", tc.TraceID, cid)
+	}
+}
+
+func TestHTTPTraceContextFromContext(t *testing.T) {
+	tests := []struct {
+		name    string
+		ctx     context.Context
+		traceID string
+	}{
+		{
+			name: "trace_context",
+			ctx: TraceContextToContext(context.Background(), TraceContext{
+				TraceID:  "0af7651916cd43dd8448eb211c80319c",
+				ParentID: "b7ad6b7169203331",
+				Flags:    1,
+			}),
+			traceID: "0af7651916cd43dd8448eb211c80319c",
+		},
+		{
+			name:    "uuid_correlation_id",
+			ctx:     ToContext(context.Background(), "2d8dcdc8-ffd6-4a04-a7b7-f2fd0e5e0d9a"),
+			traceID: "2d8dcdc8ffd64a04a7b7f2fd0e5e0d9a",
+		},
+		{
+			name: "random",
+			ctx:  ToContext(context.Background(), "1234"),
+		},
+	}
+
+	for _, test := range tests {
+		test := test
+
+		t.Run(test.name, func(t *testing.T) {
+			req := &http.Request{Header: http.Header{}}
+
+			_ = HTTPTraceContextFromContext()(test.ctx, req)
+
+			tc, ok := ParseTraceParent(req.Header.Get("traceparent"))
+			if !ok {
+				t.Fatalf("invalid traceparent header: %q", req.Header.Get("traceparent"))
+			}
+
+			if test.traceID != "" && test.traceID != tc.TraceID {
+				t.Errorf("unexpected trace ID\nexpected: %s\nactual:   %s", test.traceID, tc.TraceID)
+			}
+
+			if tc.ParentID == "b7ad6b7169203331" {
+				t.Error("a new parent ID is supposed to be generated")
+			}
+		})
+	}
+}
+
+func TestGRPCTraceContextFromContext(t *testing.T) {
+	ctx := TraceContextToContext(context.Background(), TraceContext{
+		TraceID:  "0af7651916cd43dd8448eb211c80319c",
+		ParentID: "b7ad6b7169203331",
+		Flags:    1,
+		State:    "congo=t61rcWkgMzE",
+	})
+
+	md := metadata.MD{}
+
+	_ = GRPCTraceContextFromContext()(ctx, &md)
+
+	tc, ok := ParseTraceParent(md.Get("traceparent")[0])
+	if !ok {
+		t.Fatalf("invalid traceparent header: %q", md.Get("traceparent"))
+	}
+
+	if want, have := "0af7651916cd43dd8448eb211c80319c", tc.TraceID; want != have {
+		t.Errorf("unexpected trace ID\nexpected: %s\nactual:   %s", want, have)
+	}
+
+	if want, have := byte(1), tc.Flags; want != have {
+		t.Errorf("unexpected flags\nexpected: %d\nactual:   %d", want, have)
+	}
+
+	if want, have := "congo=t61rcWkgMzE", md.Get("tracestate"); len(have) != 1 || want != have[0] {
+		t.Errorf("unexpected trace state\nexpected: %s\nactual:   %v", want, have)
+	}
+}
@@ -7,13 +7,40 @@ import (
 	"github.com/go-kit/kit/transport/grpc"
 	"github.com/go-kit/kit/transport/http"
 	"google.golang.org/grpc/metadata"
+
+	"github.com/sagikazarmark/kitx/transport/graphql"
 )
 
+// DefaultHeader is the default header (and gRPC metadata key) carrying the correlation ID.
+//
 // Note: capital letters are invalid in HTTP/2.
-const defaultCorrelationHeader = "correlation-id"
+const DefaultHeader = defaultCorrelationHeader
+
+// Note: capital letters are invalid in HTTP/2.
+const (
+	defaultCorrelationHeader = "correlation-id"
+	defaultRequestIDHeader   = "request-id"
+	defaultCausationHeader   = "causation-id"
+)
 
 // HTTPToContext moves a correlation ID from request header to context (if any).
+//
+// When no correlation ID is found, a correlation ID generated later by Middleware
+// is made available to HTTPToResponse.
 func HTTPToContext(headers ...string) http.RequestFunc {
+	return httpToContext(nil, headers)
+}
+
+// HTTPToContextWithPolicy moves a correlation ID from request header to context (if any).
+// The correlation ID is validated using the given policy.
+//
+// When no (valid) correlation ID is found, a correlation ID generated later by Middleware
+// is made available to HTTPToResponse.
+func HTTPToContextWithPolicy(policy ValidationPolicy, headers ...string) http.RequestFunc {
+	return httpToContext(&policy, headers)
+}
+
+func httpToContext(policy *ValidationPolicy, headers []string) http.RequestFunc {
 	if len(headers) == 0 {
 		headers = []string{defaultCorrelationHeader}
 	}
@@ -22,16 +49,32 @@ func HTTPToContext(headers ...string) http.RequestFunc {
 		for _, header := range headers {
 			cid := r.Header.Get(header)
 			if cid != "" {
-				return context.WithValue(ctx, correlationIDContextKey, cid)
+				return toContext(ctx, policy, cid)
 			}
 		}
 
-		return ctx
+		return withHolder(ctx)
 	}
 }
 
 // GRPCToContext moves a correlation ID from request header to context (if any).
+//
+// When no correlation ID is found, a correlation ID generated later by Middleware
+// is made available to GRPCToResponseHeader and GRPCToResponseTrailer.
 func GRPCToContext(headers ...string) grpc.ServerRequestFunc {
+	return grpcToContext(nil, headers)
+}
+
+// GRPCToContextWithPolicy moves a correlation ID from request header to context (if any).
+// The correlation ID is validated using the given policy.
+//
+// When no (valid) correlation ID is found, a correlation ID generated later by Middleware
+// is made available to GRPCToResponseHeader and GRPCToResponseTrailer.
+func GRPCToContextWithPolicy(policy ValidationPolicy, headers ...string) grpc.ServerRequestFunc {
+	return grpcToContext(&policy, headers)
+}
+
+func grpcToContext(policy *ValidationPolicy, headers []string) grpc.ServerRequestFunc {
 	if len(headers) == 0 {
 		headers = []string{defaultCorrelationHeader}
 	}
@@ -39,11 +82,266 @@ func GRPCToContext(headers ...string) grpc.ServerRequestFunc {
 	return func(ctx context.Context, md metadata.MD) context.Context {
 		for _, header := range headers {
 			cid, ok := md[header]
-			if ok && cid[0] != "" {
-				return context.WithValue(ctx, correlationIDContextKey, cid[0])
+			if ok && len(cid) > 0 && cid[0] != "" {
+				return toContext(ctx, policy, cid[0])
+			}
+		}
+
+		return withHolder(ctx)
+	}
+}
+
+// HTTPToContextOrGenerate moves a correlation ID from request header to context
+// or generates a new one if none is found.
+// If generator is nil, the same generator is used as in Middleware.
+//
+// Unlike Middleware, it runs before the request is decoded,
+// so error encoders and error handlers also see the correlation ID.
+func HTTPToContextOrGenerate(generator Generator, headers ...string) http.RequestFunc {
+	toContext := HTTPToContext(headers...)
+
+	return func(ctx context.Context, r *stdhttp.Request) context.Context {
+		return ensureID(toContext(ctx, r), generator)
+	}
+}
+
+// GRPCToContextOrGenerate moves a correlation ID from request header to context
+// or generates a new one if none is found.
+// If generator is nil, the same generator is used as in Middleware.
+//
+// Unlike Middleware, it runs before the request is decoded,
+// so error encoders and error handlers also see the correlation ID.
+func GRPCToContextOrGenerate(generator Generator, headers ...string) grpc.ServerRequestFunc {
+	toContext := GRPCToContext(headers...)
+
+	return func(ctx context.Context, md metadata.MD) context.Context {
+		return ensureID(toContext(ctx, md), generator)
+	}
+}
+
+// GraphQLGenerate generates a new correlation ID if the context does not contain one yet.
+// If generator is nil, the same generator is used as in Middleware.
+//
+// GraphQL requests are usually served over HTTP, so the correlation ID should be extracted from the request header
+// by the HTTP layer (eg. using HTTPMiddleware).
+func GraphQLGenerate(generator Generator) graphql.ServerRequestFunc {
+	return func(ctx context.Context, _ interface{}) context.Context {
+		return ensureID(ctx, generator)
+	}
+}
+
+func toContext(ctx context.Context, policy *ValidationPolicy, cid string) context.Context {
+	if policy == nil {
+		return context.WithValue(ctx, correlationIDContextKey, cid)
+	}
+
+	return policy.apply(ctx, cid)
+}
+
+// HTTPFromContext moves a correlation ID from context to request header (if any).
+// It is designed to be used in HTTP clients.
+//
+// When multiple headers are given, the correlation ID is set in all of them.
+func HTTPFromContext(headers ...string) http.RequestFunc {
+	if len(headers) == 0 {
+		headers = []string{defaultCorrelationHeader}
+	}
+
+	return httpValueFromContext(correlationIDContextKey, headers)
+}
+
+// GRPCFromContext moves a correlation ID from context to request metadata (if any).
+// It is designed to be used in gRPC clients.
+//
+// When multiple headers are given, the correlation ID is set in all of them.
+func GRPCFromContext(headers ...string) grpc.ClientRequestFunc {
+	if len(headers) == 0 {
+		headers = []string{defaultCorrelationHeader}
+	}
+
+	return grpcValueFromContext(correlationIDContextKey, headers)
+}
+
+// HTTPToResponse moves a correlation ID from context to response header (if any).
+// It is designed to be used in HTTP servers.
+//
+// When multiple headers are given, the correlation ID is set in all of them.
+func HTTPToResponse(headers ...string) http.ServerResponseFunc {
+	if len(headers) == 0 {
+		headers = []string{defaultCorrelationHeader}
+	}
+
+	return func(ctx context.Context, w stdhttp.ResponseWriter) context.Context {
+		cid, ok := fromContextOrHolder(ctx)
+		if !ok {
+			return ctx
+		}
+
+		for _, header := range headers {
+			w.Header().Set(header, cid)
+		}
+
+		return ctx
+	}
+}
+
+// GRPCToResponseHeader moves a correlation ID from context to response header metadata (if any).
+// It is designed to be used in gRPC servers.
+//
+// When multiple headers are given, the correlation ID is set in all of them.
+func GRPCToResponseHeader(headers ...string) grpc.ServerResponseFunc {
+	if len(headers) == 0 {
+		headers = []string{defaultCorrelationHeader}
+	}
+
+	return func(ctx context.Context, header *metadata.MD, _ *metadata.MD) context.Context {
+		return grpcToMetadata(ctx, header, headers)
+	}
+}
+
+// GRPCToResponseTrailer moves a correlation ID from context to response trailer metadata (if any).
+// It is designed to be used in gRPC servers.
+//
+// When multiple headers are given, the correlation ID is set in all of them.
+func GRPCToResponseTrailer(headers ...string) grpc.ServerResponseFunc {
+	if len(headers) == 0 {
+		headers = []string{defaultCorrelationHeader}
+	}
+
+	return func(ctx context.Context, _ *metadata.MD, trailer *metadata.MD) context.Context {
+		return grpcToMetadata(ctx, trailer, headers)
+	}
+}
+
+func grpcToMetadata(ctx context.Context, md *metadata.MD, headers []string) context.Context {
+	cid, ok := fromContextOrHolder(ctx)
+	if !ok {
+		return ctx
+	}
+
+	if *md == nil {
+		*md = metadata.MD{}
+	}
+
+	for _, header := range headers {
+		md.Set(header, cid)
+	}
+
+	return ctx
+}
+
+// HTTPRequestIDToContext moves a request ID from request header to context (if any).
+//
+// It is useful when a component in front of the service (eg. a load balancer) assigns a request ID to every request.
+func HTTPRequestIDToContext(headers ...string) http.RequestFunc {
+	if len(headers) == 0 {
+		headers = []string{defaultRequestIDHeader}
+	}
+
+	return httpValueToContext(requestIDContextKey, headers)
+}
+
+// GRPCRequestIDToContext moves a request ID from request header to context (if any).
+//
+// It is useful when a component in front of the service (eg. a load balancer) assigns a request ID to every request.
+func GRPCRequestIDToContext(headers ...string) grpc.ServerRequestFunc {
+	if len(headers) == 0 {
+		headers = []string{defaultRequestIDHeader}
+	}
+
+	return grpcValueToContext(requestIDContextKey, headers)
+}
+
+// HTTPCausationIDToContext moves a causation ID from request header to context (if any).
+func HTTPCausationIDToContext(headers ...string) http.RequestFunc {
+	if len(headers) == 0 {
+		headers = []string{defaultCausationHeader}
+	}
+
+	return httpValueToContext(causationIDContextKey, headers)
+}
+
+// GRPCCausationIDToContext moves a causation ID from request header to context (if any).
+func GRPCCausationIDToContext(headers ...string) grpc.ServerRequestFunc {
+	if len(headers) == 0 {
+		headers = []string{defaultCausationHeader}
+	}
+
+	return grpcValueToContext(causationIDContextKey, headers)
+}
+
+// HTTPCausationIDFromContext moves the request ID of the current hop from context to request header
+// as the causation ID of the outgoing request (if any).
+// It is designed to be used in HTTP clients.
+func HTTPCausationIDFromContext(headers ...string) http.RequestFunc {
+	if len(headers) == 0 {
+		headers = []string{defaultCausationHeader}
+	}
+
+	return httpValueFromContext(requestIDContextKey, headers)
+}
+
+// GRPCCausationIDFromContext moves the request ID of the current hop from context to request metadata
+// as the causation ID of the outgoing request (if any).
+// It is designed to be used in gRPC clients.
+func GRPCCausationIDFromContext(headers ...string) grpc.ClientRequestFunc {
+	if len(headers) == 0 {
+		headers = []string{defaultCausationHeader}
+	}
+
+	return grpcValueFromContext(requestIDContextKey, headers)
+}
+
+func httpValueToContext(key contextKey, headers []string) http.RequestFunc {
+	return func(ctx context.Context, r *stdhttp.Request) context.Context {
+		for _, header := range headers {
+			if value := r.Header.Get(header); value != "" {
+				return context.WithValue(ctx, key, value)
+			}
+		}
+
+		return ctx
+	}
+}
+
+func grpcValueToContext(key contextKey, headers []string) grpc.ServerRequestFunc {
+	return func(ctx context.Context, md metadata.MD) context.Context {
+		for _, header := range headers {
+			if values := md.Get(header); len(values) > 0 && values[0] != "" {
+				return context.WithValue(ctx, key, values[0])
 			}
 		}
 
 		return ctx
 	}
 }
+
+func httpValueFromContext(key contextKey, headers []string) http.RequestFunc {
+	return func(ctx context.Context, r *stdhttp.Request) context.Context {
+		value, ok := ctx.Value(key).(string)
+		if !ok || value == "" {
+			return ctx
+		}
+
+		for _, header := range headers {
+			r.Header.Set(header, value)
+		}
+
+		return ctx
+	}
+}
+
+func grpcValueFromContext(key contextKey, headers []string) grpc.ClientRequestFunc {
+	return func(ctx context.Context, md *metadata.MD) context.Context {
+		value, ok := ctx.Value(key).(string)
+		if !ok || value == "" {
+			return ctx
+		}
+
+		for _, header := range headers {
+			md.Set(header, value)
+		}
+
+		return ctx
+	}
+}
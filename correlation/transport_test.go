@@ -2,10 +2,16 @@ package correlation
 
 import (
 	"context"
+	"errors"
 	"net/http"
+	"net/http/httptest"
 	"testing"
 
+	"github.com/go-kit/kit/transport"
+	kithttp "github.com/go-kit/kit/transport/http"
 	"google.golang.org/grpc/metadata"
+
+	"github.com/sagikazarmark/kitx/transport/graphql"
 )
 
 func TestHTTPToContext(t *testing.T) {
@@ -107,3 +113,364 @@ func TestGRPCToContext(t *testing.T) {
 		}
 	})
 }
+
+func TestHTTPFromContext(t *testing.T) {
+	reqFunc := HTTPFromContext()
+
+	t.Run("no_correlation_id", func(t *testing.T) {
+		req := &http.Request{Header: http.Header{}}
+
+		_ = reqFunc(context.Background(), req)
+
+		if len(req.Header) > 0 {
+			t.Error("request should not contain any headers")
+		}
+	})
+
+	t.Run("default_header", func(t *testing.T) {
+		req := &http.Request{Header: http.Header{}}
+
+		_ = reqFunc(ToContext(context.Background(), "2314"), req)
+
+		if want, have := "2314", req.Header.Get("Correlation-ID"); want != have {
+			t.Errorf("unexpected correlation ID header\nexpected: %s\nactual:   %s", want, have)
+		}
+	})
+
+	t.Run("custom_header", func(t *testing.T) {
+		reqFunc := HTTPFromContext("Correlation-ID", "X-Correlation-ID")
+
+		req := &http.Request{Header: http.Header{}}
+
+		_ = reqFunc(ToContext(context.Background(), "3412"), req)
+
+		for _, header := range []string{"Correlation-ID", "X-Correlation-ID"} {
+			if want, have := "3412", req.Header.Get(header); want != have {
+				t.Errorf("unexpected %s header\nexpected: %s\nactual:   %s", header, want, have)
+			}
+		}
+	})
+}
+
+func TestGRPCFromContext(t *testing.T) {
+	reqFunc := GRPCFromContext()
+
+	t.Run("no_correlation_id", func(t *testing.T) {
+		md := metadata.MD{}
+
+		_ = reqFunc(context.Background(), &md)
+
+		if md.Len() > 0 {
+			t.Error("metadata should not contain any headers")
+		}
+	})
+
+	t.Run("default_header", func(t *testing.T) {
+		md := metadata.MD{}
+
+		_ = reqFunc(ToContext(context.Background(), "2431"), &md)
+
+		if want, have := "2431", md.Get("correlation-id"); len(have) != 1 || want != have[0] {
+			t.Errorf("unexpected correlation ID header\nexpected: %s\nactual:   %v", want, have)
+		}
+	})
+
+	t.Run("custom_header", func(t *testing.T) {
+		reqFunc := GRPCFromContext("correlation-id", "x-correlation-id")
+
+		md := metadata.MD{}
+
+		_ = reqFunc(ToContext(context.Background(), "1234"), &md)
+
+		for _, header := range []string{"correlation-id", "x-correlation-id"} {
+			if want, have := "1234", md.Get(header); len(have) != 1 || want != have[0] {
+				t.Errorf("unexpected %s header\nexpected: %v\nactual:   %v", header, want, have)
+			}
+		}
+	})
+}
+
+func TestRoundTrip(t *testing.T) {
+	t.Run("http", func(t *testing.T) {
+		req := &http.Request{Header: http.Header{}}
+
+		_ = HTTPFromContext()(ToContext(context.Background(), "1234"), req)
+		ctx := HTTPToContext()(context.Background(), req)
+
+		if cid, _ := FromContext(ctx); cid != "1234" {
+			t.Errorf("unexpected correlation ID\nexpected: %s\nactual:   %s", "1234", cid)
+		}
+	})
+
+	t.Run("grpc", func(t *testing.T) {
+		md := metadata.MD{}
+
+		_ = GRPCFromContext()(ToContext(context.Background(), "1234"), &md)
+		ctx := GRPCToContext()(context.Background(), md)
+
+		if cid, _ := FromContext(ctx); cid != "1234" {
+			t.Errorf("unexpected correlation ID\nexpected: %s\nactual:   %s", "1234", cid)
+		}
+	})
+}
+
+func TestHTTPToResponse(t *testing.T) {
+	respFunc := HTTPToResponse()
+
+	t.Run("no_correlation_id", func(t *testing.T) {
+		w := httptest.NewRecorder()
+
+		_ = respFunc(context.Background(), w)
+
+		if len(w.Header()) > 0 {
+			t.Error("response should not contain any headers")
+		}
+	})
+
+	t.Run("default_header", func(t *testing.T) {
+		w := httptest.NewRecorder()
+
+		_ = respFunc(ToContext(context.Background(), "2314"), w)
+
+		if want, have := "2314", w.Header().Get("Correlation-ID"); want != have {
+			t.Errorf("unexpected correlation ID header\nexpected: %s\nactual:   %s", want, have)
+		}
+	})
+
+	t.Run("custom_header", func(t *testing.T) {
+		respFunc := HTTPToResponse("X-Correlation-ID")
+
+		w := httptest.NewRecorder()
+
+		_ = respFunc(ToContext(context.Background(), "3412"), w)
+
+		if want, have := "3412", w.Header().Get("X-Correlation-ID"); want != have {
+			t.Errorf("unexpected correlation ID header\nexpected: %s\nactual:   %s", want, have)
+		}
+	})
+
+	t.Run("generated", func(t *testing.T) {
+		var cid string
+
+		handler := kithttp.NewServer(
+			Middleware()(func(ctx context.Context, _ interface{}) (interface{}, error) {
+				cid, _ = FromContext(ctx)
+
+				return nil, nil
+			}),
+			kithttp.NopRequestDecoder,
+			func(context.Context, http.ResponseWriter, interface{}) error { return nil },
+			kithttp.ServerBefore(HTTPToContext()),
+			kithttp.ServerAfter(HTTPToResponse()),
+		)
+
+		w := httptest.NewRecorder()
+
+		handler.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/", nil))
+
+		if cid == "" {
+			t.Fatal("correlation ID is supposed to be generated")
+		}
+
+		if want, have := cid, w.Header().Get("Correlation-ID"); want != have {
+			t.Errorf("unexpected correlation ID header\nexpected: %s\nactual:   %s", want, have)
+		}
+	})
+}
+
+func TestGRPCToResponseHeader(t *testing.T) {
+	respFunc := GRPCToResponseHeader()
+
+	t.Run("no_correlation_id", func(t *testing.T) {
+		var header, trailer metadata.MD
+
+		_ = respFunc(context.Background(), &header, &trailer)
+
+		if header.Len() > 0 || trailer.Len() > 0 {
+			t.Error("metadata should not contain any headers")
+		}
+	})
+
+	t.Run("default_header", func(t *testing.T) {
+		var header, trailer metadata.MD
+
+		_ = respFunc(ToContext(context.Background(), "2431"), &header, &trailer)
+
+		if want, have := "2431", header.Get("correlation-id"); len(have) != 1 || want != have[0] {
+			t.Errorf("unexpected correlation ID header\nexpected: %s\nactual:   %v", want, have)
+		}
+
+		if trailer.Len() > 0 {
+			t.Error("trailer should not contain any headers")
+		}
+	})
+
+	t.Run("generated", func(t *testing.T) {
+		var cid string
+		var header, trailer metadata.MD
+
+		ctx := GRPCToContext()(context.Background(), metadata.MD{})
+
+		_, _ = Middleware()(func(ctx context.Context, _ interface{}) (interface{}, error) {
+			cid, _ = FromContext(ctx)
+
+			return nil, nil
+		})(ctx, nil)
+
+		_ = respFunc(ctx, &header, &trailer)
+
+		if want, have := cid, header.Get("correlation-id"); len(have) != 1 || want != have[0] {
+			t.Errorf("unexpected correlation ID header\nexpected: %s\nactual:   %v", want, have)
+		}
+	})
+}
+
+func TestGRPCToResponseTrailer(t *testing.T) {
+	respFunc := GRPCToResponseTrailer("x-correlation-id")
+
+	var header, trailer metadata.MD
+
+	_ = respFunc(ToContext(context.Background(), "1234"), &header, &trailer)
+
+	if want, have := "1234", trailer.Get("x-correlation-id"); len(have) != 1 || want != have[0] {
+		t.Errorf("unexpected correlation ID trailer\nexpected: %s\nactual:   %v", want, have)
+	}
+
+	if header.Len() > 0 {
+		t.Error("header should not contain any headers")
+	}
+}
+
+func TestHTTPRequestIDToContext(t *testing.T) {
+	header := http.Header{}
+	header.Set("Request-ID", "1234")
+	header.Set("Causation-ID", "4321")
+
+	req := &http.Request{Header: header}
+
+	ctx := HTTPRequestIDToContext()(context.Background(), req)
+	ctx = HTTPCausationIDToContext()(ctx, req)
+
+	if rid, _ := RequestIDFromContext(ctx); rid != "1234" {
+		t.Errorf("unexpected request ID\nexpected: %s\nactual:   %s", "1234", rid)
+	}
+
+	if cid, _ := CausationIDFromContext(ctx); cid != "4321" {
+		t.Errorf("unexpected causation ID\nexpected: %s\nactual:   %s", "4321", cid)
+	}
+
+	ctx = HTTPRequestIDToContext("X-Request-ID")(context.Background(), req)
+
+	if _, ok := RequestIDFromContext(ctx); ok {
+		t.Error("context should not contain a request ID")
+	}
+}
+
+func TestGRPCRequestIDToContext(t *testing.T) {
+	md := metadata.Pairs("x-request-id", "1234", "causation-id", "4321")
+
+	ctx := GRPCRequestIDToContext("x-request-id")(context.Background(), md)
+	ctx = GRPCCausationIDToContext()(ctx, md)
+
+	if rid, _ := RequestIDFromContext(ctx); rid != "1234" {
+		t.Errorf("unexpected request ID\nexpected: %s\nactual:   %s", "1234", rid)
+	}
+
+	if cid, _ := CausationIDFromContext(ctx); cid != "4321" {
+		t.Errorf("unexpected causation ID\nexpected: %s\nactual:   %s", "4321", cid)
+	}
+}
+
+func TestCausationIDFromContext(t *testing.T) {
+	// The request ID of the current hop becomes the causation ID of the outgoing request
+	ctx := CausationIDToContext(RequestIDToContext(context.Background(), "1234"), "4321")
+
+	t.Run("http", func(t *testing.T) {
+		req := &http.Request{Header: http.Header{}}
+
+		_ = HTTPCausationIDFromContext()(ctx, req)
+
+		if want, have := "1234", req.Header.Get("Causation-ID"); want != have {
+			t.Errorf("unexpected causation ID header\nexpected: %s\nactual:   %s", want, have)
+		}
+	})
+
+	t.Run("grpc", func(t *testing.T) {
+		md := metadata.MD{}
+
+		_ = GRPCCausationIDFromContext()(ctx, &md)
+
+		if want, have := "1234", md.Get("causation-id"); len(have) != 1 || want != have[0] {
+			t.Errorf("unexpected causation ID header\nexpected: %s\nactual:   %v", want, have)
+		}
+	})
+}
+
+func TestHTTPToContextOrGenerate(t *testing.T) {
+	var cid string
+
+	handler := kithttp.NewServer(
+		func(context.Context, interface{}) (interface{}, error) { return nil, nil },
+		func(context.Context, *http.Request) (interface{}, error) { return nil, errors.New("decoding failed") },
+		func(context.Context, http.ResponseWriter, interface{}) error { return nil },
+		kithttp.ServerBefore(HTTPToContextOrGenerate(GeneratorFunc(func() string { return "generated" }))),
+		kithttp.ServerErrorEncoder(func(ctx context.Context, _ error, _ http.ResponseWriter) {
+			cid, _ = FromContext(ctx)
+		}),
+	)
+
+	t.Run("existing", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		req.Header.Set("Correlation-ID", "1234")
+
+		handler.ServeHTTP(httptest.NewRecorder(), req)
+
+		if want, have := "1234", cid; want != have {
+			t.Errorf("unexpected correlation ID\nexpected: %s\nactual:   %s", want, have)
+		}
+	})
+
+	t.Run("generated", func(t *testing.T) {
+		handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+
+		if want, have := "generated", cid; want != have {
+			t.Errorf("unexpected correlation ID\nexpected: %s\nactual:   %s", want, have)
+		}
+	})
+}
+
+func TestGRPCToContextOrGenerate(t *testing.T) {
+	reqFunc := GRPCToContextOrGenerate(nil)
+
+	ctx := reqFunc(context.Background(), metadata.Pairs("correlation-id", "1234"))
+
+	if cid, _ := FromContext(ctx); cid != "1234" {
+		t.Errorf("unexpected correlation ID\nexpected: %s\nactual:   %s", "1234", cid)
+	}
+
+	ctx = reqFunc(context.Background(), metadata.MD{})
+
+	if cid, _ := FromContext(ctx); cid == "" {
+		t.Error("correlation ID is supposed to be generated")
+	}
+}
+
+func TestGraphQLGenerate(t *testing.T) {
+	var cid string
+
+	server := graphql.NewServer(
+		func(context.Context, interface{}) (interface{}, error) { return nil, nil },
+		func(context.Context, interface{}) (interface{}, error) { return nil, errors.New("decoding failed") },
+		func(context.Context, interface{}) (interface{}, error) { return nil, nil },
+		graphql.ServerBefore(GraphQLGenerate(GeneratorFunc(func() string { return "generated" }))),
+		graphql.ServerErrorHandler(transport.ErrorHandlerFunc(func(ctx context.Context, _ error) {
+			cid, _ = FromContext(ctx)
+		})),
+	)
+
+	_, _, _ = server.ServeGraphQL(context.Background(), nil) // nolint: dogsled
+
+	if want, have := "generated", cid; want != have {
+		t.Errorf("unexpected correlation ID\nexpected: %s\nactual:   %s", want, have)
+	}
+}
@@ -0,0 +1,116 @@
+package correlation
+
+import (
+	"context"
+	"regexp"
+	"strings"
+)
+
+// UUIDPattern is a regular expression matching UUIDs (in their canonical, hyphenated form).
+const UUIDPattern = `^[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}$`
+
+// RejectAction decides what happens with a correlation ID rejected by a ValidationPolicy.
+type RejectAction int
+
+const (
+	// RejectDrop drops rejected correlation IDs.
+	RejectDrop RejectAction = iota
+
+	// RejectReplace replaces rejected correlation IDs with a newly generated one.
+	RejectReplace
+
+	// RejectUntrusted drops rejected correlation IDs,
+	// but keeps the original value in the context (see UntrustedFromContext).
+	RejectUntrusted
+)
+
+// ValidationPolicy describes how inbound correlation IDs are validated.
+//
+// Correlation IDs containing characters outside of the printable ASCII range (including whitespace)
+// are always rejected.
+type ValidationPolicy struct {
+	// MaxLength is the maximum length of a correlation ID (in bytes).
+	// Zero means no limit.
+	MaxLength int
+
+	// Charset is the list of allowed characters.
+	// Empty means every printable ASCII character is allowed.
+	Charset string
+
+	// Pattern is a regular expression that correlation IDs must match (eg. UUIDPattern).
+	// Nil means no pattern is enforced.
+	Pattern *regexp.Regexp
+
+	// Action decides what happens with rejected correlation IDs.
+	Action RejectAction
+
+	// Generator generates new correlation IDs when Action is RejectReplace.
+	// Defaults to the same generator as Middleware.
+	Generator Generator
+}
+
+// Validate checks if a correlation ID satisfies the policy.
+func (p ValidationPolicy) Validate(id string) bool {
+	if id == "" {
+		return false
+	}
+
+	if p.MaxLength > 0 && len(id) > p.MaxLength {
+		return false
+	}
+
+	for i := 0; i < len(id); i++ {
+		if id[i] < '!' || id[i] > '~' {
+			return false
+		}
+	}
+
+	if p.Charset != "" {
+		for _, c := range id {
+			if !strings.ContainsRune(p.Charset, c) {
+				return false
+			}
+		}
+	}
+
+	if p.Pattern != nil && !p.Pattern.MatchString(id) {
+		return false
+	}
+
+	return true
+}
+
+// apply validates a correlation ID and stores it (or its replacement) in the context.
+func (p ValidationPolicy) apply(ctx context.Context, id string) context.Context {
+	if p.Validate(id) {
+		return context.WithValue(ctx, correlationIDContextKey, id)
+	}
+
+	switch p.Action {
+	case RejectReplace:
+		generator := p.Generator
+		if generator == nil {
+			generator = GeneratorFunc(generate)
+		}
+
+		return context.WithValue(ctx, correlationIDContextKey, generator.Generate())
+
+	case RejectUntrusted:
+		ctx = context.WithValue(ctx, untrustedCorrelationIDContextKey, id)
+	}
+
+	return withHolder(ctx)
+}
+
+// untrustedCorrelationIDContextKey holds the key used to store a rejected correlation ID in the context.
+const untrustedCorrelationIDContextKey contextKey = "UntrustedCorrelationID"
+
+// UntrustedFromContext returns a correlation ID rejected by a ValidationPolicy from the context (if any).
+// Returns false as the second parameter if none is found.
+//
+// The returned value is not validated: make sure to escape it before writing it to logs.
+func UntrustedFromContext(ctx context.Context) (string, bool) {
+	id, ok := ctx.Value(untrustedCorrelationIDContextKey).(string)
+
+	return id, ok
+}
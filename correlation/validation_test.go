@@ -0,0 +1,174 @@
+package correlation
+
+import (
+	"context"
+	"net/http"
+	"regexp"
+	"strings"
+	"testing"
+
+	"google.golang.org/grpc/metadata"
+)
+
+func TestValidationPolicy_Validate(t *testing.T) {
+	tests := []struct {
+		name   string
+		policy ValidationPolicy
+		id     string
+		valid  bool
+	}{
+		{
+			name:  "empty",
+			id:    "",
+			valid: false,
+		},
+		{
+			name:  "printable",
+			id:    "abc-123_!~",
+			valid: true,
+		},
+		{
+			name:  "newline",
+			id:    "abc\n123",
+			valid: false,
+		},
+		{
+			name:  "whitespace",
+			id:    "abc 123",
+			valid: false,
+		},
+		{
+			name:  "non_ascii",
+			id:    "abcé",
+			valid: false,
+		},
+		{
+			name:   "max_length",
+			policy: ValidationPolicy{MaxLength: 4},
+			id:     "abcd",
+			valid:  true,
+		},
+		{
+			name:   "too_long",
+			policy: ValidationPolicy{MaxLength: 4},
+			id:     strings.Repeat("a", 5),
+			valid:  false,
+		},
+		{
+			name:   "charset",
+			policy: ValidationPolicy{Charset: "abc123"},
+			id:     "a1b2c3",
+			valid:  true,
+		},
+		{
+			name:   "invalid_charset",
+			policy: ValidationPolicy{Charset: "abc123"},
+			id:     "a1b2c3d4",
+			valid:  false,
+		},
+		{
+			name:   "uuid",
+			policy: ValidationPolicy{Pattern: regexp.MustCompile(UUIDPattern)},
+			id:     "2d8dcdc8-ffd6-4a04-a7b7-f2fd0e5e0d9a",
+			valid:  true,
+		},
+		{
+			name:   "invalid_uuid",
+			policy: ValidationPolicy{Pattern: regexp.MustCompile(UUIDPattern)},
+			id:     "2d8dcdc8ffd64a04a7b7f2fd0e5e0d9a",
+			valid:  false,
+		},
+	}
+
+	for _, test := range tests {
+		test := test
+
+		t.Run(test.name, func(t *testing.T) {
+			if want, have := test.valid, test.policy.Validate(test.id); want != have {
+				t.Errorf("unexpected validation result for %q\nexpected: %t\nactual:   %t", test.id, want, have)
+			}
+		})
+	}
+}
+
+func TestHTTPToContextWithPolicy(t *testing.T) {
+	const invalidID = "1234\nlevel=error msg=forged"
+
+	header := http.Header{}
+	header.Set("Correlation-ID", invalidID)
+
+	t.Run("valid", func(t *testing.T) {
+		header := http.Header{}
+		header.Set("Correlation-ID", "1234")
+
+		ctx := HTTPToContextWithPolicy(ValidationPolicy{})(context.Background(), &http.Request{Header: header})
+
+		if cid, _ := FromContext(ctx); cid != "1234" {
+			t.Errorf("unexpected correlation ID\nexpected: %s\nactual:   %s", "1234", cid)
+		}
+	})
+
+	t.Run("drop", func(t *testing.T) {
+		ctx := HTTPToContextWithPolicy(ValidationPolicy{})(context.Background(), &http.Request{Header: header})
+
+		if _, ok := FromContext(ctx); ok {
+			t.Error("context should not contain the encoded correlation ID")
+		}
+
+		if _, ok := UntrustedFromContext(ctx); ok {
+			t.Error("context should not contain the untrusted correlation ID")
+		}
+	})
+
+	t.Run("replace", func(t *testing.T) {
+		policy := ValidationPolicy{
+			Action:    RejectReplace,
+			Generator: GeneratorFunc(func() string { return "generated" }),
+		}
+
+		ctx := HTTPToContextWithPolicy(policy)(context.Background(), &http.Request{Header: header})
+
+		if cid, _ := FromContext(ctx); cid != "generated" {
+			t.Errorf("unexpected correlation ID\nexpected: %s\nactual:   %s", "generated", cid)
+		}
+	})
+
+	t.Run("untrusted", func(t *testing.T) {
+		ctx := HTTPToContextWithPolicy(ValidationPolicy{Action: RejectUntrusted})(
+			context.Background(),
+			&http.Request{Header: header},
+		)
+
+		if _, ok := FromContext(ctx); ok {
+			t.Error("context should not contain the encoded correlation ID")
+		}
+
+		if cid, _ := UntrustedFromContext(ctx); cid != invalidID {
+			t.Errorf("unexpected untrusted correlation ID\nexpected: %q\nactual:   %q", invalidID, cid)
+		}
+	})
+}
+
+func TestGRPCToContextWithPolicy(t *testing.T) {
+	policy := ValidationPolicy{MaxLength: 4, Action: RejectUntrusted}
+
+	t.Run("valid", func(t *testing.T) {
+		ctx := GRPCToContextWithPolicy(policy)(context.Background(), metadata.Pairs("correlation-id", "1234"))
+
+		if cid, _ := FromContext(ctx); cid != "1234" {
+			t.Errorf("unexpected correlation ID\nexpected: %s\nactual:   %s", "1234", cid)
+		}
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		ctx := GRPCToContextWithPolicy(policy)(context.Background(), metadata.Pairs("correlation-id", "12345"))
+
+		if _, ok := FromContext(ctx); ok {
+			t.Error("context should not contain the encoded correlation ID")
+		}
+
+		if cid, _ := UntrustedFromContext(ctx); cid != "12345" {
+			t.Errorf("unexpected untrusted correlation ID\nexpected: %s\nactual:   %s", "12345", cid)
+		}
+	})
+}
@@ -0,0 +1,335 @@
+package endpoint
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/go-kit/kit/endpoint"
+)
+
+// CircuitState is the state of a circuit breaker.
+type CircuitState int
+
+// Circuit breaker states.
+const (
+	// CircuitClosed lets every call through.
+	CircuitClosed CircuitState = iota
+
+	// CircuitOpen rejects every call.
+	CircuitOpen
+
+	// CircuitHalfOpen lets a limited number of calls through to probe whether the operation recovered.
+	CircuitHalfOpen
+)
+
+// String implements fmt.Stringer.
+func (s CircuitState) String() string {
+	switch s {
+	case CircuitClosed:
+		return "closed"
+
+	case CircuitOpen:
+		return "open"
+
+	case CircuitHalfOpen:
+		return "half-open"
+
+	default:
+		return fmt.Sprintf("unknown state %d", int(s))
+	}
+}
+
+// CircuitOpenError is returned by CircuitBreakerMiddleware when the circuit breaker of an operation rejects a call.
+type CircuitOpenError struct {
+	// Operation is the name of the operation whose circuit breaker is open.
+	Operation string
+}
+
+// Error implements the error interface.
+func (e *CircuitOpenError) Error() string {
+	return fmt.Sprintf("circuit breaker is open for operation %q", e.Operation)
+}
+
+// CircuitBreakerOption configures CircuitBreakerMiddleware.
+type CircuitBreakerOption interface {
+	apply(o *circuitBreakerOptions)
+}
+
+type circuitBreakerOptions struct {
+	consecutiveFailures uint32
+	failureRatio        float64
+	minRequests         uint32
+	interval            time.Duration
+	openTimeout         time.Duration
+	halfOpenRequests    uint32
+	errorMatcher        ErrorMatcher
+	countFailed         bool
+	onStateChange       func(name string, from CircuitState, to CircuitState)
+}
+
+type circuitBreakerOptionFunc func(o *circuitBreakerOptions)
+
+func (fn circuitBreakerOptionFunc) apply(o *circuitBreakerOptions) {
+	fn(o)
+}
+
+// CircuitConsecutiveFailures opens the circuit after a number of consecutive failures.
+// Zero disables the threshold.
+//
+// By default, the circuit opens after 5 consecutive failures.
+func CircuitConsecutiveFailures(failures uint32) CircuitBreakerOption {
+	return circuitBreakerOptionFunc(func(o *circuitBreakerOptions) { o.consecutiveFailures = failures })
+}
+
+// CircuitFailureRatio opens the circuit when the ratio of failures (between 0 and 1) reaches a threshold
+// within an interval (see CircuitInterval), provided that at least minRequests calls were made.
+//
+// By default, the failure ratio is not checked.
+func CircuitFailureRatio(ratio float64, minRequests uint32) CircuitBreakerOption {
+	return circuitBreakerOptionFunc(func(o *circuitBreakerOptions) {
+		o.failureRatio = ratio
+		o.minRequests = minRequests
+	})
+}
+
+// CircuitInterval sets the interval after which the failure counts of a closed circuit are reset.
+//
+// By default, the counts are reset every minute.
+func CircuitInterval(interval time.Duration) CircuitBreakerOption {
+	return circuitBreakerOptionFunc(func(o *circuitBreakerOptions) { o.interval = interval })
+}
+
+// CircuitOpenTimeout sets the time an open circuit waits before becoming half-open.
+//
+// By default, an open circuit becomes half-open after 30 seconds.
+func CircuitOpenTimeout(timeout time.Duration) CircuitBreakerOption {
+	return circuitBreakerOptionFunc(func(o *circuitBreakerOptions) { o.openTimeout = timeout })
+}
+
+// CircuitHalfOpenRequests sets the number of calls let through by a half-open circuit.
+// The circuit closes if all of them succeed.
+// Zero is treated as one.
+//
+// By default, a single call is let through.
+func CircuitHalfOpenRequests(requests uint32) CircuitBreakerOption {
+	return circuitBreakerOptionFunc(func(o *circuitBreakerOptions) { o.halfOpenRequests = requests })
+}
+
+// CircuitErrorMatcher sets the ErrorMatcher deciding which errors count as failures.
+//
+// By default, every error counts as a failure.
+func CircuitErrorMatcher(errorMatcher ErrorMatcher) CircuitBreakerOption {
+	return circuitBreakerOptionFunc(func(o *circuitBreakerOptions) { o.errorMatcher = errorMatcher })
+}
+
+// CircuitCountFailed counts failed responses (see endpoint.Failer) matching the ErrorMatcher as failures.
+//
+// By default, failed responses are considered business errors and do not count as failures.
+func CircuitCountFailed() CircuitBreakerOption {
+	return circuitBreakerOptionFunc(func(o *circuitBreakerOptions) { o.countFailed = true })
+}
+
+// CircuitStateChange registers a function called when the state of a circuit changes.
+// The function is called synchronously, so it should not block.
+func CircuitStateChange(fn func(name string, from CircuitState, to CircuitState)) CircuitBreakerOption {
+	return circuitBreakerOptionFunc(func(o *circuitBreakerOptions) { o.onStateChange = fn })
+}
+
+// CircuitBreakerMiddleware returns a MiddlewareFactory that keeps an independent circuit breaker per operation.
+//
+// While the circuit of an operation is open, calls are rejected with a *CircuitOpenError.
+// A panicking call counts as a failure (the panic is propagated).
+func CircuitBreakerMiddleware(opts ...CircuitBreakerOption) MiddlewareFactory {
+	o := circuitBreakerOptions{
+		consecutiveFailures: 5,
+		interval:            time.Minute,
+		openTimeout:         30 * time.Second,
+		halfOpenRequests:    1,
+	}
+
+	for _, opt := range opts {
+		opt.apply(&o)
+	}
+
+	if o.errorMatcher == nil {
+		o.errorMatcher = func(error) bool { return true }
+	}
+
+	if o.halfOpenRequests == 0 {
+		o.halfOpenRequests = 1
+	}
+
+	return NameMiddlewareFactory("circuit_breaker", func(name string) endpoint.Middleware {
+		cb := &circuitBreaker{
+			name:    name,
+			options: o,
+		}
+		cb.newGeneration(time.Now())
+
+		return func(next endpoint.Endpoint) endpoint.Endpoint {
+			return func(ctx context.Context, request interface{}) (response interface{}, err error) {
+				generation, ok := cb.allow(time.Now())
+				if !ok {
+					return nil, &CircuitOpenError{Operation: name}
+				}
+
+				// Record the result even if the call panics, otherwise a half-open circuit would never close
+				panicked := true
+
+				defer func() {
+					cb.record(time.Now(), generation, panicked || cb.failed(response, err))
+				}()
+
+				response, err = next(ctx, request)
+				panicked = false
+
+				return response, err
+			}
+		}
+	})
+}
+
+// circuitCounts holds the call counts of a circuit breaker in the current generation.
+type circuitCounts struct {
+	requests             uint32
+	failures             uint32
+	consecutiveFailures  uint32
+	consecutiveSuccesses uint32
+}
+
+// circuitBreaker is the circuit breaker of a single operation.
+//
+// Every state change (and every interval in the closed state) starts a new generation,
+// so results of calls started in a previous generation are ignored.
+type circuitBreaker struct {
+	name    string
+	options circuitBreakerOptions
+
+	mu         sync.Mutex
+	state      CircuitState
+	generation uint64
+	counts     circuitCounts
+	expiry     time.Time
+}
+
+func (cb *circuitBreaker) failed(response interface{}, err error) bool {
+	if err == nil && !cb.options.countFailed {
+		return false
+	}
+
+	err = callError(response, err)
+
+	return err != nil && cb.options.errorMatcher(err)
+}
+
+// allow checks whether a call is allowed and returns the current generation.
+func (cb *circuitBreaker) allow(now time.Time) (uint64, bool) {
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+
+	cb.refresh(now)
+
+	switch {
+	case cb.state == CircuitOpen:
+		return cb.generation, false
+
+	case cb.state == CircuitHalfOpen && cb.counts.requests >= cb.options.halfOpenRequests:
+		return cb.generation, false
+	}
+
+	cb.counts.requests++
+
+	return cb.generation, true
+}
+
+// record records the result of a call started in a generation.
+func (cb *circuitBreaker) record(now time.Time, generation uint64, failed bool) {
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+
+	cb.refresh(now)
+
+	if generation != cb.generation {
+		return
+	}
+
+	if failed {
+		cb.counts.failures++
+		cb.counts.consecutiveFailures++
+		cb.counts.consecutiveSuccesses = 0
+
+		if cb.state == CircuitHalfOpen || cb.tripped() {
+			cb.setState(now, CircuitOpen)
+		}
+
+		return
+	}
+
+	cb.counts.consecutiveSuccesses++
+	cb.counts.consecutiveFailures = 0
+
+	if cb.state == CircuitHalfOpen && cb.counts.consecutiveSuccesses >= cb.options.halfOpenRequests {
+		cb.setState(now, CircuitClosed)
+	}
+}
+
+func (cb *circuitBreaker) tripped() bool {
+	if cb.options.consecutiveFailures > 0 && cb.counts.consecutiveFailures >= cb.options.consecutiveFailures {
+		return true
+	}
+
+	return cb.options.failureRatio > 0 &&
+		cb.counts.requests >= cb.options.minRequests &&
+		float64(cb.counts.failures)/float64(cb.counts.requests) >= cb.options.failureRatio
+}
+
+// refresh moves the circuit to the next generation when the current one expires.
+func (cb *circuitBreaker) refresh(now time.Time) {
+	if cb.expiry.IsZero() || now.Before(cb.expiry) {
+		return
+	}
+
+	switch cb.state {
+	case CircuitClosed:
+		cb.newGeneration(now)
+
+	case CircuitOpen:
+		cb.setState(now, CircuitHalfOpen)
+	}
+}
+
+func (cb *circuitBreaker) setState(now time.Time, state CircuitState) {
+	if cb.state == state {
+		return
+	}
+
+	from := cb.state
+	cb.state = state
+
+	cb.newGeneration(now)
+
+	if cb.options.onStateChange != nil {
+		cb.options.onStateChange(cb.name, from, state)
+	}
+}
+
+func (cb *circuitBreaker) newGeneration(now time.Time) {
+	cb.generation++
+	cb.counts = circuitCounts{}
+
+	switch cb.state {
+	case CircuitClosed:
+		cb.expiry = time.Time{}
+		if cb.options.interval > 0 {
+			cb.expiry = now.Add(cb.options.interval)
+		}
+
+	case CircuitOpen:
+		cb.expiry = now.Add(cb.options.openTimeout)
+
+	default:
+		cb.expiry = time.Time{}
+	}
+}
@@ -0,0 +1,262 @@
+package endpoint
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+)
+
+type stateChange struct {
+	from CircuitState
+	to   CircuitState
+}
+
+func TestCircuitBreakerMiddleware(t *testing.T) {
+	var (
+		fail    = true
+		calls   int
+		changes []stateChange
+	)
+
+	mf := CircuitBreakerMiddleware(
+		CircuitConsecutiveFailures(2),
+		CircuitOpenTimeout(20*time.Millisecond),
+		CircuitStateChange(func(name string, from CircuitState, to CircuitState) {
+			if name != "greet" {
+				t.Errorf("unexpected operation name: %s", name)
+			}
+
+			changes = append(changes, stateChange{from, to})
+		}),
+	)
+
+	e := mf("greet")(func(context.Context, interface{}) (interface{}, error) {
+		calls++
+
+		if fail {
+			return nil, errors.New("error")
+		}
+
+		return "response", nil
+	})
+
+	for i := 0; i < 2; i++ {
+		_, _ = e(context.Background(), nil)
+	}
+
+	_, err := e(context.Background(), nil)
+
+	var openErr *CircuitOpenError
+	if !errors.As(err, &openErr) {
+		t.Fatalf("unexpected error\nexpected: %T\nactual:   %v", openErr, err)
+	}
+
+	if want, have := 2, calls; want != have {
+		t.Errorf("unexpected number of calls\nexpected: %d\nactual:   %d", want, have)
+	}
+
+	time.Sleep(30 * time.Millisecond)
+
+	// Failing probe opens the circuit again
+	_, _ = e(context.Background(), nil)
+
+	if _, err := e(context.Background(), nil); !errors.As(err, &openErr) {
+		t.Errorf("unexpected error\nexpected: %T\nactual:   %v", openErr, err)
+	}
+
+	time.Sleep(30 * time.Millisecond)
+
+	fail = false
+
+	// Successful probe closes the circuit
+	if _, err := e(context.Background(), nil); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := e(context.Background(), nil); err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []stateChange{
+		{CircuitClosed, CircuitOpen},
+		{CircuitOpen, CircuitHalfOpen},
+		{CircuitHalfOpen, CircuitOpen},
+		{CircuitOpen, CircuitHalfOpen},
+		{CircuitHalfOpen, CircuitClosed},
+	}
+
+	if want, have := fmt.Sprint(expected), fmt.Sprint(changes); want != have {
+		t.Errorf("unexpected state changes\nexpected: %s\nactual:   %s", want, have)
+	}
+}
+
+func TestCircuitBreakerMiddleware_PerOperation(t *testing.T) {
+	mf := CircuitBreakerMiddleware(CircuitConsecutiveFailures(1))
+
+	failing := mf("fail")(func(context.Context, interface{}) (interface{}, error) { return nil, errors.New("error") })
+	succeeding := mf("succeed")(func(context.Context, interface{}) (interface{}, error) { return "response", nil })
+
+	_, _ = failing(context.Background(), nil)
+
+	var openErr *CircuitOpenError
+	if _, err := failing(context.Background(), nil); !errors.As(err, &openErr) || openErr.Operation != "fail" {
+		t.Errorf("unexpected error: %v", err)
+	}
+
+	if _, err := succeeding(context.Background(), nil); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
+
+func TestCircuitBreakerMiddleware_FailureRatio(t *testing.T) {
+	var calls int
+
+	e := CircuitBreakerMiddleware(
+		CircuitConsecutiveFailures(0),
+		CircuitFailureRatio(0.5, 4),
+	)("greet")(func(context.Context, interface{}) (interface{}, error) {
+		calls++
+
+		if calls%2 == 0 {
+			return nil, errors.New("error")
+		}
+
+		return "response", nil
+	})
+
+	for i := 0; i < 4; i++ {
+		if _, err := e(context.Background(), nil); errors.As(err, new(*CircuitOpenError)) {
+			t.Fatalf("circuit opened too early (call %d)", i+1)
+		}
+	}
+
+	if _, err := e(context.Background(), nil); !errors.As(err, new(*CircuitOpenError)) {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
+
+func TestCircuitBreakerMiddleware_ErrorMatcher(t *testing.T) {
+	errIgnored := errors.New("ignored")
+
+	tests := []struct {
+		name     string
+		endpoint func(context.Context, interface{}) (interface{}, error)
+		opts     []CircuitBreakerOption
+		open     bool
+	}{
+		{
+			name:     "ignored error",
+			endpoint: func(context.Context, interface{}) (interface{}, error) { return nil, errIgnored },
+			opts: []CircuitBreakerOption{
+				CircuitErrorMatcher(func(err error) bool { return !errors.Is(err, errIgnored) }),
+			},
+		},
+		{
+			name:     "failed response",
+			endpoint: func(context.Context, interface{}) (interface{}, error) { return NewFailure(errors.New("error")), nil },
+		},
+		{
+			name:     "counted failed response",
+			endpoint: func(context.Context, interface{}) (interface{}, error) { return NewFailure(errors.New("error")), nil },
+			opts:     []CircuitBreakerOption{CircuitCountFailed()},
+			open:     true,
+		},
+	}
+
+	for _, test := range tests {
+		test := test
+
+		t.Run(test.name, func(t *testing.T) {
+			e := CircuitBreakerMiddleware(append(test.opts, CircuitConsecutiveFailures(1))...)("greet")(test.endpoint)
+
+			_, _ = e(context.Background(), nil)
+			_, err := e(context.Background(), nil)
+
+			if want, have := test.open, errors.As(err, new(*CircuitOpenError)); want != have {
+				t.Errorf("unexpected circuit state\nexpected open: %v\nactual open:   %v", want, have)
+			}
+		})
+	}
+}
+
+func TestCircuitBreakerMiddleware_Panic(t *testing.T) {
+	var panics bool
+
+	e := CircuitBreakerMiddleware(
+		CircuitConsecutiveFailures(1),
+		CircuitOpenTimeout(20*time.Millisecond),
+	)("greet")(func(context.Context, interface{}) (interface{}, error) {
+		if panics {
+			panic("oops")
+		}
+
+		return nil, errors.New("error")
+	})
+
+	call := func() (err error) {
+		defer func() {
+			if v := recover(); v != nil {
+				err = fmt.Errorf("panic: %v", v)
+			}
+		}()
+
+		_, err = e(context.Background(), nil)
+
+		return err
+	}
+
+	// Open the circuit
+	_ = call()
+
+	time.Sleep(30 * time.Millisecond)
+
+	// Panicking probe opens the circuit again
+	panics = true
+
+	if err := call(); err == nil || errors.As(err, new(*CircuitOpenError)) {
+		t.Fatalf("expected the probe to panic, got: %v", err)
+	}
+
+	if err := call(); !errors.As(err, new(*CircuitOpenError)) {
+		t.Errorf("unexpected error\nexpected: %T\nactual:   %v", new(*CircuitOpenError), err)
+	}
+
+	time.Sleep(30 * time.Millisecond)
+
+	// The circuit lets a new probe through
+	if err := call(); err == nil || errors.As(err, new(*CircuitOpenError)) {
+		t.Errorf("expected a new probe to be let through, got: %v", err)
+	}
+}
+
+func TestCircuitBreakerMiddleware_ZeroHalfOpenRequests(t *testing.T) {
+	fail := true
+
+	e := CircuitBreakerMiddleware(
+		CircuitConsecutiveFailures(1),
+		CircuitOpenTimeout(20*time.Millisecond),
+		CircuitHalfOpenRequests(0),
+	)("greet")(func(context.Context, interface{}) (interface{}, error) {
+		if fail {
+			return nil, errors.New("error")
+		}
+
+		return "response", nil
+	})
+
+	_, _ = e(context.Background(), nil)
+
+	time.Sleep(30 * time.Millisecond)
+
+	fail = false
+
+	if _, err := e(context.Background(), nil); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if _, err := e(context.Background(), nil); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
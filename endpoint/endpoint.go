@@ -15,7 +15,11 @@ func Chain(mw ...endpoint.Middleware) func(endpoint.Endpoint) endpoint.Endpoint
 
 // Combine composes a single middleware from a list.
 // Compared to endpoint.Chain, this function accepts a variadic list.
+//
+// Nil middleware (eg. returned by a MiddlewareFactory not applying to an operation) is skipped.
 func Combine(mw ...endpoint.Middleware) func(endpoint.Endpoint) endpoint.Endpoint {
+	mw = nonNilMiddleware(mw)
+
 	if len(mw) == 0 {
 		return func(e endpoint.Endpoint) endpoint.Endpoint {
 			return e
@@ -31,25 +35,33 @@ func Combine(mw ...endpoint.Middleware) func(endpoint.Endpoint) endpoint.Endpoin
 	}
 }
 
-// ErrorMatcher is a predicate for errors.
-// It can be used in middleware to decide whether to take action or not.
-type ErrorMatcher func(err error) bool
+// nonNilMiddleware returns the list of middleware without nil entries.
+func nonNilMiddleware(mw []endpoint.Middleware) []endpoint.Middleware {
+	filtered := make([]endpoint.Middleware, 0, len(mw))
 
-type failer struct {
-	err error
-}
+	for _, m := range mw {
+		if m != nil {
+			filtered = append(filtered, m)
+		}
+	}
 
-func (f failer) Failed() error {
-	return f.err
+	return filtered
 }
 
+// ErrorMatcher is a predicate for errors.
+// It can be used in middleware to decide whether to take action or not.
+//
+// ErrorMatchers can be combined using And, Or and Not.
+type ErrorMatcher func(err error) bool
+
 // FailerMiddleware checks if a returned error matches a predicate and wraps it in a failer response if it does.
-func FailerMiddleware(errorMatcher ErrorMatcher) endpoint.Middleware {
+// The failer response is a *Failure with the metadata attached by the options.
+func FailerMiddleware(errorMatcher ErrorMatcher, opts ...FailureOption) endpoint.Middleware {
 	return func(e endpoint.Endpoint) endpoint.Endpoint {
 		return func(ctx context.Context, request interface{}) (interface{}, error) {
 			resp, err := e(ctx, request)
 			if err != nil && errorMatcher(err) {
-				return failer{err}, nil
+				return NewFailure(err, opts...), nil
 			}
 
 			return resp, err
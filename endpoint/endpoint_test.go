@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"net/http"
 	"testing"
 
 	"github.com/go-kit/kit/endpoint"
@@ -153,6 +154,25 @@ func TestFailerMiddleware(t *testing.T) {
 			t.Errorf("unexpected response\nexpected: %s\nactual:   %s", response, resp)
 		}
 	})
+
+	t.Run("metadata", func(t *testing.T) {
+		var e endpoint.Endpoint = func(ctx context.Context, request interface{}) (interface{}, error) {
+			return nil, errors.New("error")
+		}
+
+		e = FailerMiddleware(func(err error) bool { return err != nil }, WithHTTPStatus(http.StatusConflict))(e)
+
+		resp, _ := e(context.Background(), nil)
+
+		failure, ok := AsFailure(resp)
+		if !ok {
+			t.Fatal("response is supposed to be a failure response")
+		}
+
+		if want, have := http.StatusConflict, failure.HTTPStatus; want != have {
+			t.Errorf("unexpected HTTP status\nexpected: %d\nactual:   %d", want, have)
+		}
+	})
 }
 
 func TestOperationNameMiddleware(t *testing.T) {
@@ -174,3 +194,20 @@ func TestOperationNameMiddleware(t *testing.T) {
 		t.Fatalf("unexpected endpoint name, wanted %q, got %q", want, have)
 	}
 }
+
+func TestCombine_NilMiddleware(t *testing.T) {
+	e := Combine(
+		TimeoutMiddleware(0)("greet"),
+		RateLimitMiddleware(RateLimit{})("greet"),
+		Include(MatchGlob("other"), Middleware(Combine()))("greet"),
+	)(func(context.Context, interface{}) (interface{}, error) { return "response", nil })
+
+	response, err := e(context.Background(), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if want, have := "response", response; want != have {
+		t.Errorf("unexpected response\nexpected: %v\nactual:   %v", want, have)
+	}
+}
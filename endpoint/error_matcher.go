@@ -0,0 +1,142 @@
+package endpoint
+
+import (
+	"context"
+	"errors"
+	"reflect"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// And returns an ErrorMatcher that matches errors matching all of the matchers.
+func And(matchers ...ErrorMatcher) ErrorMatcher {
+	return func(err error) bool {
+		for _, matcher := range matchers {
+			if !matcher(err) {
+				return false
+			}
+		}
+
+		return true
+	}
+}
+
+// Or returns an ErrorMatcher that matches errors matching any of the matchers.
+func Or(matchers ...ErrorMatcher) ErrorMatcher {
+	return func(err error) bool {
+		for _, matcher := range matchers {
+			if matcher(err) {
+				return true
+			}
+		}
+
+		return false
+	}
+}
+
+// Not returns an ErrorMatcher that matches errors not matching the matcher.
+func Not(matcher ErrorMatcher) ErrorMatcher {
+	return func(err error) bool {
+		return !matcher(err)
+	}
+}
+
+// Is returns an ErrorMatcher that matches errors matching any of the targets using errors.Is.
+func Is(targets ...error) ErrorMatcher {
+	return func(err error) bool {
+		for _, target := range targets {
+			if errors.Is(err, target) {
+				return true
+			}
+		}
+
+		return false
+	}
+}
+
+// errorType is the reflect.Type of the error interface.
+// nolint: gochecknoglobals
+var errorType = reflect.TypeOf((*error)(nil)).Elem()
+
+// As returns an ErrorMatcher that matches errors assignable to T using errors.As.
+// T must be an interface or a type implementing error (see errors.As), otherwise As panics.
+func As[T any]() ErrorMatcher {
+	// Check the target type upfront: errors.As would panic when the matcher is first called
+	if typ := reflect.TypeOf((*T)(nil)).Elem(); typ.Kind() != reflect.Interface && !typ.Implements(errorType) {
+		panic("endpoint: As type parameter " + typ.String() + " must be an interface or implement error")
+	}
+
+	return func(err error) bool {
+		var target T
+
+		return errors.As(err, &target)
+	}
+}
+
+// HasBehavior returns an ErrorMatcher that matches errors implementing a behavior interface T
+// (eg. interface{ ClientError() bool }) for which the behavior function returns true:
+//
+//	HasBehavior(func(err interface{ ClientError() bool }) bool { return err.ClientError() })
+//
+// The error chain is traversed the same way as in errors.Is.
+// T must be an interface type, otherwise HasBehavior panics.
+func HasBehavior[T any](behavior func(T) bool) ErrorMatcher {
+	if typ := reflect.TypeOf((*T)(nil)).Elem(); typ.Kind() != reflect.Interface {
+		panic("endpoint: HasBehavior type parameter " + typ.String() + " must be an interface")
+	}
+
+	return func(err error) bool {
+		return hasBehavior(err, behavior)
+	}
+}
+
+func hasBehavior[T any](err error, behavior func(T) bool) bool {
+	for err != nil {
+		if b, ok := any(err).(T); ok && behavior(b) {
+			return true
+		}
+
+		switch e := err.(type) { // nolint: errorlint
+		case interface{ Unwrap() error }:
+			err = e.Unwrap()
+
+		case interface{ Unwrap() []error }:
+			for _, err := range e.Unwrap() {
+				if hasBehavior(err, behavior) {
+					return true
+				}
+			}
+
+			return false
+
+		default:
+			return false
+		}
+	}
+
+	return false
+}
+
+// IsContextError is an ErrorMatcher that matches context cancellation and deadline errors.
+func IsContextError(err error) bool {
+	return errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded)
+}
+
+// IsGRPCCode returns an ErrorMatcher that matches gRPC status errors with any of the codes.
+func IsGRPCCode(targets ...codes.Code) ErrorMatcher {
+	return func(err error) bool {
+		st, ok := status.FromError(err)
+		if !ok || err == nil {
+			return false
+		}
+
+		for _, code := range targets {
+			if st.Code() == code {
+				return true
+			}
+		}
+
+		return false
+	}
+}
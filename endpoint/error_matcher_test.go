@@ -0,0 +1,93 @@
+package endpoint
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+type clientError struct {
+	client bool
+}
+
+func (e clientError) Error() string {
+	return "client error"
+}
+
+func (e clientError) ClientError() bool {
+	return e.client
+}
+
+func TestErrorMatchers(t *testing.T) {
+	isClientError := HasBehavior(func(err interface{ ClientError() bool }) bool { return err.ClientError() })
+
+	errTarget := errors.New("target")
+	errOther := errors.New("other")
+
+	always := func(error) bool { return true }
+	never := func(error) bool { return false }
+
+	tests := []struct {
+		name     string
+		matcher  ErrorMatcher
+		err      error
+		expected bool
+	}{
+		{"and", And(always, always), errOther, true},
+		{"and/false", And(always, never), errOther, false},
+		{"or", Or(never, always), errOther, true},
+		{"or/false", Or(never, never), errOther, false},
+		{"not", Not(never), errOther, true},
+		{"is", Is(errOther, errTarget), fmt.Errorf("wrapped: %w", errTarget), true},
+		{"is/false", Is(errTarget), errOther, false},
+		{"as", As[clientError](), fmt.Errorf("wrapped: %w", clientError{}), true},
+		{"as/interface", As[interface{ ClientError() bool }](), fmt.Errorf("wrapped: %w", clientError{}), true},
+		{"as/false", As[clientError](), errOther, false},
+		{"behavior", isClientError, fmt.Errorf("wrapped: %w", clientError{true}), true},
+		{"behavior/joined", isClientError, errors.Join(errOther, clientError{true}), true},
+		{"behavior/false", isClientError, clientError{false}, false},
+		{"behavior/missing", isClientError, errOther, false},
+		{"context/canceled", IsContextError, fmt.Errorf("wrapped: %w", context.Canceled), true},
+		{"context/deadline", IsContextError, &TimeoutError{}, true},
+		{"context/false", IsContextError, errOther, false},
+		{"grpc", IsGRPCCode(codes.NotFound, codes.Unavailable), status.Error(codes.Unavailable, "unavailable"), true},
+		{"grpc/wrapped", IsGRPCCode(codes.Unavailable), fmt.Errorf("wrapped: %w", status.Error(codes.Unavailable, "unavailable")), true},
+		{"grpc/false", IsGRPCCode(codes.NotFound), status.Error(codes.Unavailable, "unavailable"), false},
+		{"grpc/not status", IsGRPCCode(codes.Unknown), errOther, false},
+		{"grpc/nil", IsGRPCCode(codes.OK), nil, false},
+	}
+
+	for _, test := range tests {
+		test := test
+
+		t.Run(test.name, func(t *testing.T) {
+			if want, have := test.expected, test.matcher(test.err); want != have {
+				t.Errorf("unexpected match result\nexpected: %v\nactual:   %v", want, have)
+			}
+		})
+	}
+}
+
+func TestAs_InvalidType(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Error("As is supposed to panic for types that are neither interfaces nor errors")
+		}
+	}()
+
+	As[string]()
+}
+
+func TestHasBehavior_InvalidType(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Error("HasBehavior is supposed to panic for types that are not interfaces")
+		}
+	}()
+
+	HasBehavior(func(clientError) bool { return true })
+}
@@ -1,6 +1,10 @@
 package endpoint
 
 import (
+	"reflect"
+	"strconv"
+	"sync"
+
 	"github.com/go-kit/kit/endpoint"
 )
 
@@ -11,7 +15,18 @@ type Factory interface {
 	NewEndpoint(name string, e endpoint.Endpoint) endpoint.Endpoint
 }
 
+// Describer is an optional interface that MAY be implemented by a Factory.
+// It describes which middleware a Factory applies to an operation.
+type Describer interface {
+	// DescribeOperation returns the names of the middleware applied to an operation (in order).
+	DescribeOperation(name string) []string
+}
+
 // MiddlewareFactory creates a middleware per operation.
+//
+// A MiddlewareFactory MAY return nil, in which case no middleware is applied to the operation.
+// Factory and Combine skip nil middleware: use them instead of calling the returned middleware directly
+// (eg. Combine(mf(name))(e) instead of mf(name)(e)).
 type MiddlewareFactory func(name string) endpoint.Middleware
 
 // Middleware wraps singleton middleware and wraps them in a MiddlewareFactory.
@@ -21,35 +36,151 @@ func Middleware(middleware endpoint.Middleware) MiddlewareFactory {
 	}
 }
 
+var (
+	middlewareFactoryNamesMu sync.RWMutex
+	middlewareFactoryNames   = map[uintptr]string{}
+)
+
+// NameMiddlewareFactory names every MiddlewareFactory created by the same function as middlewareFactory.
+// The name is used to describe which middleware is applied to an operation (see Describer).
+//
+// It is meant to be used by middleware factory constructors (eg. LoggingMiddleware names its factories "logging").
+// Middleware factories created by the same function with different names (eg. Middleware, Include or Exclude)
+// remain unnamed: use FactoryNamedMiddleware to name them.
+func NameMiddlewareFactory(name string, middlewareFactory MiddlewareFactory) MiddlewareFactory {
+	key := reflect.ValueOf(middlewareFactory).Pointer()
+
+	middlewareFactoryNamesMu.Lock()
+	defer middlewareFactoryNamesMu.Unlock()
+
+	if n, ok := middlewareFactoryNames[key]; ok && n != name {
+		name = ""
+	}
+
+	middlewareFactoryNames[key] = name
+
+	return middlewareFactory
+}
+
+// middlewareFactoryName returns the name of a middleware factory (if any).
+func middlewareFactoryName(middlewareFactory MiddlewareFactory) string {
+	middlewareFactoryNamesMu.RLock()
+	defer middlewareFactoryNamesMu.RUnlock()
+
+	return middlewareFactoryNames[reflect.ValueOf(middlewareFactory).Pointer()]
+}
+
 // NewFactory returns a new Factory.
 func NewFactory(middlewareFactories ...MiddlewareFactory) Factory {
-	return factory{
-		middlewareFactories: middlewareFactories,
+	return NewFactoryWithOptions(FactoryMiddleware(middlewareFactories...))
+}
+
+// NewFactoryWithOptions returns a new Factory configured by options.
+func NewFactoryWithOptions(opts ...FactoryOption) Factory {
+	f := factory{
+		operations: NewRegistry(),
+	}
+
+	for _, opt := range opts {
+		opt.apply(&f)
 	}
+
+	return f
+}
+
+// FactoryOption configures a Factory.
+type FactoryOption interface {
+	apply(f *factory)
+}
+
+type factoryOptionFunc func(f *factory)
+
+func (fn factoryOptionFunc) apply(f *factory) {
+	fn(f)
+}
+
+// FactoryMiddleware adds middleware factories to a Factory.
+// Middleware factories are named by NameMiddlewareFactory (if any).
+func FactoryMiddleware(middlewareFactories ...MiddlewareFactory) FactoryOption {
+	return factoryOptionFunc(func(f *factory) {
+		for _, mf := range middlewareFactories {
+			f.middlewareFactories = append(f.middlewareFactories, namedMiddlewareFactory{middlewareFactoryName(mf), mf})
+		}
+	})
+}
+
+// FactoryNamedMiddleware adds a named middleware factory to a Factory.
+// The name is used to describe which middleware is applied to an operation (see Describer).
+func FactoryNamedMiddleware(name string, middlewareFactory MiddlewareFactory) FactoryOption {
+	return factoryOptionFunc(func(f *factory) {
+		f.middlewareFactories = append(f.middlewareFactories, namedMiddlewareFactory{name, middlewareFactory})
+	})
+}
+
+// FactoryRegistry records every operation created by a Factory in a Registry.
+func FactoryRegistry(registry *Registry) FactoryOption {
+	return factoryOptionFunc(func(f *factory) { f.registry = registry })
+}
+
+type namedMiddlewareFactory struct {
+	name    string
+	factory MiddlewareFactory
 }
 
 type factory struct {
-	middlewareFactories []MiddlewareFactory
+	middlewareFactories []namedMiddlewareFactory
+	registry            *Registry
+
+	// operations records the middleware applied to every operation (see DescribeOperation).
+	operations *Registry
 }
 
 func (f factory) NewEndpoint(name string, e endpoint.Endpoint) endpoint.Endpoint {
 	if len(f.middlewareFactories) == 0 {
+		f.register(name, []string{})
+
 		return e
 	}
 
-	var m endpoint.Middleware
+	mc := make([]endpoint.Middleware, 0, len(f.middlewareFactories))
+	names := make([]string, 0, len(f.middlewareFactories))
 
-	if len(f.middlewareFactories) == 1 {
-		m = f.middlewareFactories[0](name)
-	} else {
-		mc := make([]endpoint.Middleware, 0, len(f.middlewareFactories))
-
-		for _, mf := range f.middlewareFactories {
-			mc = append(mc, mf(name))
+	for i, mf := range f.middlewareFactories {
+		if m := mf.factory(name); m != nil {
+			mc = append(mc, m)
+			names = append(names, mf.describe(i))
 		}
+	}
+
+	f.register(name, names)
+
+	return Combine(mc...)(e)
+}
+
+func (f factory) register(name string, middleware []string) {
+	f.operations.register(name, middleware)
+
+	if f.registry != nil {
+		f.registry.register(name, middleware)
+	}
+}
+
+// DescribeOperation implements the Describer interface.
+//
+// The middleware applied to an operation is recorded when the operation is created (see NewEndpoint).
+// Operations that are not created by the factory have no middleware.
+// Unnamed middleware is described by its position in the factory (eg. "middleware#0").
+func (f factory) DescribeOperation(name string) []string {
+	op, _ := f.operations.Operation(name)
+
+	return op.Middleware
+}
 
-		m = endpoint.Chain(mc[0], mc[1:]...)
+// describe returns the name of a middleware factory at a position in the factory.
+func (mf namedMiddlewareFactory) describe(i int) string {
+	if mf.name != "" {
+		return mf.name
 	}
 
-	return m(e)
+	return "middleware#" + strconv.Itoa(i)
 }
@@ -3,6 +3,7 @@ package endpoint
 import (
 	"context"
 	"fmt"
+	"reflect"
 	"testing"
 
 	"github.com/go-kit/kit/endpoint"
@@ -67,3 +68,50 @@ func TestNewFactory(t *testing.T) {
 		})
 	}
 }
+
+func TestFactory_DescribeOperation(t *testing.T) {
+	var calls int
+
+	nop := func(name string) endpoint.Middleware {
+		calls++
+
+		return func(e endpoint.Endpoint) endpoint.Endpoint { return e }
+	}
+
+	factory := NewFactoryWithOptions(
+		FactoryMiddleware(nop),
+		FactoryNamedMiddleware("auth", Exclude(MatchGlob("health.*"), nop)),
+		FactoryNamedMiddleware("timeout", Include(MatchGlob("report.*"), nop)),
+	)
+
+	describer, ok := factory.(Describer)
+	if !ok {
+		t.Fatal("factory is supposed to implement Describer")
+	}
+
+	tests := map[string][]string{
+		"health.Check":    {"middleware#0"},
+		"report.Generate": {"middleware#0", "auth", "timeout"},
+		"user.Get":        {"middleware#0", "auth"},
+	}
+
+	for name := range tests {
+		factory.NewEndpoint(name, func(context.Context, interface{}) (interface{}, error) { return nil, nil })
+	}
+
+	created := calls
+
+	for name, expected := range tests {
+		if want, have := expected, describer.DescribeOperation(name); !reflect.DeepEqual(want, have) {
+			t.Errorf("unexpected middleware for %q\nexpected: %v\nactual:   %v", name, want, have)
+		}
+	}
+
+	if have := describer.DescribeOperation("unknown"); have != nil {
+		t.Errorf("unknown operation is not expected to have middleware\nactual: %v", have)
+	}
+
+	if want, have := created, calls; want != have {
+		t.Errorf("middleware factories are not supposed to be called by DescribeOperation\nexpected: %d calls\nactual:   %d calls", want, have)
+	}
+}
@@ -0,0 +1,122 @@
+package endpoint
+
+import (
+	"errors"
+
+	"github.com/go-kit/kit/endpoint"
+	"google.golang.org/grpc/codes"
+)
+
+// Failure is a failed response (see endpoint.Failer) carrying metadata for transports.
+//
+// Failure also implements the error interface (wrapping the failure error),
+// so it can be extracted from an error chain using errors.As.
+type Failure struct {
+	// Err is the error of the failed response.
+	Err error
+
+	// HTTPStatus is the suggested HTTP status code (if not zero).
+	HTTPStatus int
+
+	// GRPCCode is the suggested gRPC status code (if not OK).
+	GRPCCode codes.Code
+
+	// Header contains headers (or gRPC metadata) added to the response.
+	Header map[string][]string
+
+	// Data is partial response data (if any) returned alongside the error by transports supporting it.
+	// Only the GraphQL transport encodes it: HTTP and gRPC error responses ignore it.
+	Data interface{}
+}
+
+// FailureOption attaches metadata to a Failure.
+type FailureOption interface {
+	apply(f *Failure)
+}
+
+type failureOptionFunc func(f *Failure)
+
+func (fn failureOptionFunc) apply(f *Failure) {
+	fn(f)
+}
+
+// WithHTTPStatus sets the suggested HTTP status code of a Failure.
+func WithHTTPStatus(code int) FailureOption {
+	return failureOptionFunc(func(f *Failure) { f.HTTPStatus = code })
+}
+
+// WithGRPCCode sets the suggested gRPC status code of a Failure.
+func WithGRPCCode(code codes.Code) FailureOption {
+	return failureOptionFunc(func(f *Failure) { f.GRPCCode = code })
+}
+
+// WithHeader adds a header to a Failure.
+func WithHeader(key string, values ...string) FailureOption {
+	return failureOptionFunc(func(f *Failure) {
+		if f.Header == nil {
+			f.Header = map[string][]string{}
+		}
+
+		f.Header[key] = append(f.Header[key], values...)
+	})
+}
+
+// WithData sets partial response data of a Failure.
+func WithData(data interface{}) FailureOption {
+	return failureOptionFunc(func(f *Failure) { f.Data = data })
+}
+
+// NewFailure returns a new Failure.
+func NewFailure(err error, opts ...FailureOption) *Failure {
+	f := &Failure{Err: err}
+
+	for _, opt := range opts {
+		opt.apply(f)
+	}
+
+	return f
+}
+
+// Failed implements endpoint.Failer.
+func (f *Failure) Failed() error {
+	return f.Err
+}
+
+// PartialData returns the partial response data (if any).
+func (f *Failure) PartialData() interface{} {
+	return f.Data
+}
+
+// Error implements the error interface.
+func (f *Failure) Error() string {
+	if f.Err == nil {
+		return "failure"
+	}
+
+	return f.Err.Error()
+}
+
+// Unwrap returns the error of the failed response.
+func (f *Failure) Unwrap() error {
+	return f.Err
+}
+
+// AsFailure finds a Failure in a response.
+// The response may be a *Failure, an error wrapping a *Failure or a failed response (see endpoint.Failer)
+// whose error wraps a *Failure.
+func AsFailure(response interface{}) (*Failure, bool) {
+	var failure *Failure
+
+	switch r := response.(type) {
+	case *Failure:
+		return r, r != nil
+
+	case error:
+		return failure, errors.As(r, &failure)
+
+	case endpoint.Failer:
+		return failure, errors.As(r.Failed(), &failure)
+	}
+
+	return nil, false
+}
@@ -0,0 +1,83 @@
+package endpoint
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"google.golang.org/grpc/codes"
+)
+
+func TestNewFailure(t *testing.T) {
+	err := errors.New("error")
+
+	failure := NewFailure(
+		err,
+		WithHTTPStatus(http.StatusConflict),
+		WithGRPCCode(codes.AlreadyExists),
+		WithHeader("X-Reason", "duplicate"),
+		WithData("partial"),
+	)
+
+	if !errors.Is(failure.Failed(), err) {
+		t.Errorf("unexpected error: %v", failure.Failed())
+	}
+
+	if want, have := http.StatusConflict, failure.HTTPStatus; want != have {
+		t.Errorf("unexpected HTTP status\nexpected: %d\nactual:   %d", want, have)
+	}
+
+	if want, have := codes.AlreadyExists, failure.GRPCCode; want != have {
+		t.Errorf("unexpected gRPC code\nexpected: %d\nactual:   %d", want, have)
+	}
+
+	if want, have := "duplicate", failure.Header["X-Reason"]; len(have) != 1 || want != have[0] {
+		t.Errorf("unexpected header\nexpected: %s\nactual:   %v", want, have)
+	}
+
+	if want, have := "partial", failure.PartialData(); want != have {
+		t.Errorf("unexpected data\nexpected: %v\nactual:   %v", want, have)
+	}
+}
+
+func TestAsFailure(t *testing.T) {
+	failure := NewFailure(errors.New("error"))
+
+	tests := []struct {
+		name     string
+		response interface{}
+		ok       bool
+	}{
+		{"failure", failure, true},
+		{"error", fmt.Errorf("wrapped: %w", failure), true},
+		{"failer", failerStub{fmt.Errorf("wrapped: %w", failure)}, true},
+		{"other failer", failerStub{errors.New("error")}, false},
+		{"response", "response", false},
+		{"nil", nil, false},
+	}
+
+	for _, test := range tests {
+		test := test
+
+		t.Run(test.name, func(t *testing.T) {
+			f, ok := AsFailure(test.response)
+
+			if want, have := test.ok, ok; want != have {
+				t.Fatalf("unexpected result\nexpected: %v\nactual:   %v", want, have)
+			}
+
+			if ok && f != failure {
+				t.Errorf("unexpected failure: %v", f)
+			}
+		})
+	}
+}
+
+type failerStub struct {
+	err error
+}
+
+func (f failerStub) Failed() error {
+	return f.err
+}
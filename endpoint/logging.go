@@ -0,0 +1,161 @@
+package endpoint
+
+import (
+	"context"
+	"log/slog"
+	"math/rand/v2"
+	"time"
+
+	"github.com/go-kit/kit/endpoint"
+	"github.com/go-kit/log"
+	"github.com/go-kit/log/level"
+
+	"github.com/sagikazarmark/kitx/correlation"
+)
+
+// LoggingOption configures a logging middleware.
+type LoggingOption interface {
+	apply(o *loggingOptions)
+}
+
+type loggingOptions struct {
+	levels            map[Outcome]level.Value
+	successSampleRate float64
+	sample            func() bool
+}
+
+type loggingOptionFunc func(o *loggingOptions)
+
+func (fn loggingOptionFunc) apply(o *loggingOptions) {
+	fn(o)
+}
+
+// LogLevel sets the log level for an outcome.
+//
+// By default, successful calls are logged at info, failed responses at warn and errors at error level.
+func LogLevel(outcome Outcome, lvl level.Value) LoggingOption {
+	return loggingOptionFunc(func(o *loggingOptions) { o.levels[outcome] = lvl })
+}
+
+// LogSuccessSampleRate sets the ratio (between 0 and 1) of successful calls that are logged.
+// Failed responses and errors are always logged.
+//
+// By default, every successful call is logged.
+func LogSuccessSampleRate(rate float64) LoggingOption {
+	return loggingOptionFunc(func(o *loggingOptions) { o.successSampleRate = rate })
+}
+
+func newLoggingOptions(opts []LoggingOption) loggingOptions {
+	o := loggingOptions{
+		levels: map[Outcome]level.Value{
+			OutcomeSuccess: level.InfoValue(),
+			OutcomeFailure: level.WarnValue(),
+			OutcomeError:   level.ErrorValue(),
+		},
+		successSampleRate: 1,
+	}
+
+	for _, opt := range opts {
+		opt.apply(&o)
+	}
+
+	if o.sample == nil {
+		o.sample = func() bool {
+			return o.successSampleRate >= 1 || rand.Float64() < o.successSampleRate // nolint: gosec
+		}
+	}
+
+	return o
+}
+
+// loggingMiddleware calls logFunc after every endpoint call with the outcome, the log level and the key-value pairs.
+func loggingMiddleware(
+	name string,
+	o loggingOptions,
+	logFunc func(ctx context.Context, lvl level.Value, keyvals ...interface{}),
+) endpoint.Middleware {
+	return func(next endpoint.Endpoint) endpoint.Endpoint {
+		return func(ctx context.Context, request interface{}) (response interface{}, err error) {
+			ctx = WithAttemptCounter(ctx, name)
+
+			defer func(begin time.Time) {
+				outcome := Classify(response, err)
+
+				if outcome == OutcomeSuccess && !o.sample() {
+					return
+				}
+
+				keyvals := []interface{}{
+					"took", time.Since(begin),
+					"failed", outcome == OutcomeFailure,
+				}
+
+				if cid, ok := correlation.FromContext(ctx); ok && cid != "" {
+					keyvals = append(keyvals, "correlation_id", cid)
+				}
+
+				if attempts := Attempts(ctx, name); attempts > 1 {
+					keyvals = append(keyvals, "attempts", attempts)
+				}
+
+				if err := callError(response, err); err != nil {
+					keyvals = append(keyvals, "err", err)
+				}
+
+				logFunc(ctx, o.levels[outcome], keyvals...)
+			}(time.Now())
+
+			return next(ctx, request)
+		}
+	}
+}
+
+// LoggingMiddleware returns a MiddlewareFactory that logs every endpoint call using a go-kit logger.
+//
+// Every log line contains the operation name, the duration of the call, the error (if any),
+// whether the response is a failed response (see endpoint.Failer), the correlation ID (if any)
+// and the number of attempts (if the call is retried by RetryMiddleware).
+func LoggingMiddleware(logger log.Logger, opts ...LoggingOption) MiddlewareFactory {
+	o := newLoggingOptions(opts)
+
+	return NameMiddlewareFactory("logging", func(name string) endpoint.Middleware {
+		logger := log.With(logger, "operation", name)
+
+		return loggingMiddleware(name, o, func(_ context.Context, lvl level.Value, keyvals ...interface{}) {
+			_ = log.WithPrefix(logger, level.Key(), lvl).Log(keyvals...)
+		})
+	})
+}
+
+// SlogLoggingMiddleware returns a MiddlewareFactory that logs every endpoint call using a slog logger.
+//
+// Every log record contains the operation name, the duration of the call, the error (if any),
+// whether the response is a failed response (see endpoint.Failer), the correlation ID (if any)
+// and the number of attempts (if the call is retried by RetryMiddleware).
+func SlogLoggingMiddleware(logger *slog.Logger, opts ...LoggingOption) MiddlewareFactory {
+	o := newLoggingOptions(opts)
+
+	return NameMiddlewareFactory("logging", func(name string) endpoint.Middleware {
+		logger := logger.With("operation", name)
+
+		return loggingMiddleware(name, o, func(ctx context.Context, lvl level.Value, keyvals ...interface{}) {
+			logger.Log(ctx, slogLevel(lvl), "endpoint call", keyvals...)
+		})
+	})
+}
+
+func slogLevel(lvl level.Value) slog.Level {
+	switch lvl {
+	case level.DebugValue():
+		return slog.LevelDebug
+
+	case level.WarnValue():
+		return slog.LevelWarn
+
+	case level.ErrorValue():
+		return slog.LevelError
+
+	default:
+		return slog.LevelInfo
+	}
+}
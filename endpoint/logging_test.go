@@ -0,0 +1,109 @@
+package endpoint
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"log/slog"
+	"strings"
+	"testing"
+
+	"github.com/go-kit/kit/endpoint"
+	"github.com/go-kit/log"
+	"github.com/go-kit/log/level"
+
+	"github.com/sagikazarmark/kitx/correlation"
+)
+
+func TestLoggingMiddleware(t *testing.T) {
+	tests := []struct {
+		name     string
+		endpoint endpoint.Endpoint
+		opts     []LoggingOption
+		expected []string
+	}{
+		{
+			name:     "success",
+			endpoint: func(context.Context, interface{}) (interface{}, error) { return "response", nil },
+			expected: []string{"level=info operation=greet took=", "failed=false correlation_id=1234\n"},
+		},
+		{
+			name: "failure",
+			endpoint: func(context.Context, interface{}) (interface{}, error) {
+				return NewFailure(errors.New("not found")), nil
+			},
+			expected: []string{"level=warn operation=greet took=", "failed=true correlation_id=1234 err=\"not found\"\n"},
+		},
+		{
+			name: "error",
+			endpoint: func(context.Context, interface{}) (interface{}, error) {
+				return nil, errors.New("connection refused")
+			},
+			opts:     []LoggingOption{LogLevel(OutcomeError, level.DebugValue())},
+			expected: []string{"level=debug operation=greet took=", "failed=false correlation_id=1234 err=\"connection refused\"\n"},
+		},
+		{
+			name: "retried",
+			endpoint: func() endpoint.Endpoint {
+				e, _ := flakyEndpoint(errRetryable)
+
+				return RetryMiddleware(isRetryable, RetryBackoff(ConstantBackoff(0)))("greet")(e)
+			}(),
+			expected: []string{"level=info operation=greet took=", "failed=false correlation_id=1234 attempts=2\n"},
+		},
+		{
+			name:     "sampled",
+			endpoint: func(context.Context, interface{}) (interface{}, error) { return "response", nil },
+			opts:     []LoggingOption{LogSuccessSampleRate(0)},
+			expected: nil,
+		},
+	}
+
+	for _, test := range tests {
+		test := test
+
+		t.Run(test.name, func(t *testing.T) {
+			var buf bytes.Buffer
+
+			e := LoggingMiddleware(log.NewLogfmtLogger(&buf), test.opts...)("greet")(test.endpoint)
+
+			_, _ = e(correlation.ToContext(context.Background(), "1234"), nil)
+
+			output := buf.String()
+
+			if len(test.expected) == 0 && output != "" {
+				t.Errorf("unexpected log output: %s", output)
+			}
+
+			for _, expected := range test.expected {
+				if !strings.Contains(output, expected) {
+					t.Errorf("log output does not contain the expected value\nexpected: %s\nactual:   %s", expected, output)
+				}
+			}
+		})
+	}
+}
+
+func TestSlogLoggingMiddleware(t *testing.T) {
+	var buf bytes.Buffer
+
+	logger := slog.New(slog.NewTextHandler(&buf, nil))
+
+	e := SlogLoggingMiddleware(logger)("greet")(func(context.Context, interface{}) (interface{}, error) {
+		return NewFailure(errors.New("not found")), nil
+	})
+
+	_, _ = e(correlation.ToContext(context.Background(), "1234"), nil)
+
+	output := buf.String()
+
+	for _, expected := range []string{
+		"level=WARN",
+		`msg="endpoint call" operation=greet took=`,
+		`failed=true correlation_id=1234 err="not found"`,
+	} {
+		if !strings.Contains(output, expected) {
+			t.Errorf("log output does not contain the expected value\nexpected: %s\nactual:   %s", expected, output)
+		}
+	}
+}
@@ -0,0 +1,58 @@
+package endpoint
+
+import (
+	"path"
+
+	"github.com/go-kit/kit/endpoint"
+)
+
+// OperationMatcher is a predicate for operation names.
+// It can be used to apply middleware to a subset of operations.
+type OperationMatcher func(name string) bool
+
+// MatchGlob returns an OperationMatcher that matches operation names against a list of glob patterns
+// (using path.Match syntax, eg. "health.*").
+// It panics if a pattern is malformed.
+//
+// Note: '*' does not match '/' characters in operation names.
+func MatchGlob(patterns ...string) OperationMatcher {
+	for _, pattern := range patterns {
+		if _, err := path.Match(pattern, ""); err != nil {
+			panic("endpoint: invalid glob pattern " + pattern + ": " + err.Error())
+		}
+	}
+
+	return func(name string) bool {
+		for _, pattern := range patterns {
+			if ok, _ := path.Match(pattern, name); ok {
+				return true
+			}
+		}
+
+		return false
+	}
+}
+
+// Include applies a middleware factory only to operations matching the matcher.
+// It returns nil for other operations (see MiddlewareFactory).
+func Include(matcher OperationMatcher, middlewareFactory MiddlewareFactory) MiddlewareFactory {
+	return func(name string) endpoint.Middleware {
+		if !matcher(name) {
+			return nil
+		}
+
+		return middlewareFactory(name)
+	}
+}
+
+// Exclude applies a middleware factory to every operation, except the ones matching the matcher.
+// It returns nil for the excluded operations (see MiddlewareFactory).
+func Exclude(matcher OperationMatcher, middlewareFactory MiddlewareFactory) MiddlewareFactory {
+	return func(name string) endpoint.Middleware {
+		if matcher(name) {
+			return nil
+		}
+
+		return middlewareFactory(name)
+	}
+}
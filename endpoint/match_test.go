@@ -0,0 +1,68 @@
+package endpoint
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/go-kit/kit/endpoint"
+)
+
+func TestMatchGlob(t *testing.T) {
+	matcher := MatchGlob("health.*", "report.Generate")
+
+	tests := map[string]bool{
+		"health.Check":    true,
+		"health.":         true,
+		"report.Generate": true,
+		"report.List":     false,
+		"user.Get":        false,
+	}
+
+	for name, match := range tests {
+		if want, have := match, matcher(name); want != have {
+			t.Errorf("unexpected match result for %q\nexpected: %t\nactual:   %t", name, want, have)
+		}
+	}
+}
+
+func TestMatchGlob_InvalidPattern(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Error("invalid pattern is supposed to panic")
+		}
+	}()
+
+	MatchGlob("[")
+}
+
+func TestIncludeExclude(t *testing.T) {
+	var called []string
+
+	mf := func(name string) endpoint.Middleware {
+		return func(next endpoint.Endpoint) endpoint.Endpoint {
+			return func(ctx context.Context, request interface{}) (interface{}, error) {
+				called = append(called, name)
+
+				return next(ctx, request)
+			}
+		}
+	}
+
+	factory := NewFactory(
+		Include(MatchGlob("report.*"), mf),
+		Exclude(func(name string) bool { return name == "health.Check" }, mf),
+	)
+
+	ep := func(context.Context, interface{}) (interface{}, error) { return nil, nil }
+
+	for _, name := range []string{"health.Check", "report.Generate", "user.Get"} {
+		_, _ = factory.NewEndpoint(name, ep)(context.Background(), nil)
+	}
+
+	expected := []string{"report.Generate", "report.Generate", "user.Get"}
+
+	if want, have := expected, called; !reflect.DeepEqual(want, have) {
+		t.Errorf("unexpected middleware calls\nexpected: %v\nactual:   %v", want, have)
+	}
+}
@@ -0,0 +1,71 @@
+package endpoint
+
+import (
+	"context"
+	"time"
+
+	"github.com/go-kit/kit/endpoint"
+	"github.com/go-kit/kit/metrics"
+)
+
+// Metric label names used by MetricsMiddleware.
+const (
+	OperationLabel = "operation"
+	OutcomeLabel   = "outcome"
+)
+
+// Metrics is a set of RED (rate, errors, duration) metrics recorded for every endpoint call.
+//
+// Any of the metrics may be nil, in which case it is not recorded.
+type Metrics struct {
+	// Requests counts every call.
+	// Labels: operation.
+	Requests metrics.Counter
+
+	// Errors counts calls with a failure or error outcome (see Outcome).
+	// Labels: operation, outcome.
+	Errors metrics.Counter
+
+	// Duration observes the duration of every call in seconds.
+	// Labels: operation, outcome.
+	Duration metrics.Histogram
+
+	// Retries counts the retry attempts made by RetryMiddleware.
+	// Labels: operation.
+	Retries metrics.Counter
+}
+
+// MetricsMiddleware returns a MiddlewareFactory that records RED metrics for every endpoint call.
+//
+// Since it relies on go-kit metrics, it works with any of the go-kit metrics backends (Prometheus, expvar, etc).
+func MetricsMiddleware(m Metrics) MiddlewareFactory {
+	return NameMiddlewareFactory("metrics", func(name string) endpoint.Middleware {
+		return func(next endpoint.Endpoint) endpoint.Endpoint {
+			return func(ctx context.Context, request interface{}) (response interface{}, err error) {
+				ctx = WithAttemptCounter(ctx, name)
+
+				defer func(begin time.Time) {
+					outcome := Classify(response, err)
+
+					if m.Requests != nil {
+						m.Requests.With(OperationLabel, name).Add(1)
+					}
+
+					if m.Errors != nil && outcome != OutcomeSuccess {
+						m.Errors.With(OperationLabel, name, OutcomeLabel, string(outcome)).Add(1)
+					}
+
+					if m.Duration != nil {
+						m.Duration.With(OperationLabel, name, OutcomeLabel, string(outcome)).Observe(time.Since(begin).Seconds())
+					}
+
+					if attempts := Attempts(ctx, name); m.Retries != nil && attempts > 1 {
+						m.Retries.With(OperationLabel, name).Add(float64(attempts - 1))
+					}
+				}(time.Now())
+
+				return next(ctx, request)
+			}
+		}
+	})
+}
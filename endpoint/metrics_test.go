@@ -0,0 +1,112 @@
+package endpoint
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/go-kit/kit/endpoint"
+	"github.com/go-kit/kit/metrics"
+	"github.com/go-kit/kit/metrics/generic"
+)
+
+// labeledCounter keeps a separate generic counter for every label value combination,
+// because generic counters returned by With do not share state.
+type labeledCounter struct {
+	counters map[string]*generic.Counter
+	lvs      []string
+}
+
+func newLabeledCounter() *labeledCounter {
+	return &labeledCounter{counters: map[string]*generic.Counter{}}
+}
+
+func (c *labeledCounter) With(labelValues ...string) metrics.Counter {
+	return &labeledCounter{counters: c.counters, lvs: append(append([]string{}, c.lvs...), labelValues...)}
+}
+
+func (c *labeledCounter) Add(delta float64) {
+	key := strings.Join(c.lvs, ",")
+
+	counter, ok := c.counters[key]
+	if !ok {
+		counter = generic.NewCounter(key)
+		c.counters[key] = counter
+	}
+
+	counter.Add(delta)
+}
+
+func (c *labeledCounter) value(labelValues ...string) float64 {
+	counter, ok := c.counters[strings.Join(labelValues, ",")]
+	if !ok {
+		return 0
+	}
+
+	return counter.Value()
+}
+
+func TestMetricsMiddleware(t *testing.T) {
+	requests := newLabeledCounter()
+	errs := newLabeledCounter()
+	duration := generic.NewSimpleHistogram()
+
+	mf := MetricsMiddleware(Metrics{
+		Requests: requests,
+		Errors:   errs,
+		Duration: duration,
+	})
+
+	endpoints := map[string]endpoint.Endpoint{
+		"success": func(context.Context, interface{}) (interface{}, error) { return "response", nil },
+		"failure": func(context.Context, interface{}) (interface{}, error) {
+			return NewFailure(errors.New("not found")), nil
+		},
+		"error": func(context.Context, interface{}) (interface{}, error) {
+			return nil, errors.New("connection refused")
+		},
+	}
+
+	for name, e := range endpoints {
+		e := mf(name)(e)
+
+		for i := 0; i < 2; i++ {
+			_, _ = e(context.Background(), nil)
+		}
+	}
+
+	for name := range endpoints {
+		if want, have := 2.0, requests.value(OperationLabel, name); want != have {
+			t.Errorf("unexpected request count for %q\nexpected: %v\nactual:   %v", name, want, have)
+		}
+	}
+
+	tests := []struct {
+		operation string
+		outcome   Outcome
+		expected  float64
+	}{
+		{"success", OutcomeSuccess, 0},
+		{"failure", OutcomeFailure, 2},
+		{"error", OutcomeError, 2},
+	}
+
+	for _, test := range tests {
+		if want, have := test.expected, errs.value(OperationLabel, test.operation, OutcomeLabel, string(test.outcome)); want != have {
+			t.Errorf("unexpected error count for %q\nexpected: %v\nactual:   %v", test.operation, want, have)
+		}
+	}
+
+	if have := duration.ApproximateMovingAverage(); have < 0 {
+		t.Errorf("unexpected duration average: %v", have)
+	}
+}
+
+func TestMetricsMiddleware_NilMetrics(t *testing.T) {
+	e := MetricsMiddleware(Metrics{})("operation")(func(context.Context, interface{}) (interface{}, error) {
+		return nil, errors.New("error")
+	})
+
+	_, _ = e(context.Background(), nil)
+}
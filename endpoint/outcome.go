@@ -0,0 +1,46 @@
+package endpoint
+
+import (
+	"github.com/go-kit/kit/endpoint"
+)
+
+// Outcome classifies the result of an endpoint call.
+type Outcome string
+
+// Endpoint call outcomes.
+const (
+	// OutcomeSuccess is a successful call.
+	OutcomeSuccess Outcome = "success"
+
+	// OutcomeFailure is a call returning a failed response (see endpoint.Failer), usually a business error.
+	OutcomeFailure Outcome = "failure"
+
+	// OutcomeError is a call returning an error, usually a transport or infrastructure error.
+	OutcomeError Outcome = "error"
+)
+
+// Classify returns the outcome of an endpoint call.
+func Classify(response interface{}, err error) Outcome {
+	if err != nil {
+		return OutcomeError
+	}
+
+	if f, ok := response.(endpoint.Failer); ok && f.Failed() != nil {
+		return OutcomeFailure
+	}
+
+	return OutcomeSuccess
+}
+
+// callError returns the error of an endpoint call: either the returned error or the error of a failed response.
+func callError(response interface{}, err error) error {
+	if err != nil {
+		return err
+	}
+
+	if f, ok := response.(endpoint.Failer); ok {
+		return f.Failed()
+	}
+
+	return nil
+}
@@ -0,0 +1,229 @@
+package endpoint
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sync"
+	"time"
+
+	"github.com/go-kit/kit/endpoint"
+)
+
+// RateLimit is a token bucket configuration.
+type RateLimit struct {
+	// Rate is the number of tokens added to the bucket per second.
+	Rate float64
+
+	// Burst is the capacity of the bucket (at least 1).
+	Burst int
+}
+
+// RateLimitError is returned by RateLimitMiddleware when a call is rejected.
+type RateLimitError struct {
+	// Operation is the name of the rate limited operation.
+	Operation string
+
+	// RetryAfter is the time to wait before the next call is allowed.
+	RetryAfter time.Duration
+}
+
+// Error implements the error interface.
+func (e *RateLimitError) Error() string {
+	return fmt.Sprintf("rate limit exceeded for operation %q (retry after %s)", e.Operation, e.RetryAfter)
+}
+
+// RateLimitStore holds token buckets.
+type RateLimitStore interface {
+	// Take takes a token from the bucket identified by key.
+	// It returns zero if a token is available, or the time to wait until the next token is available.
+	Take(ctx context.Context, key string, limit RateLimit) (time.Duration, error)
+}
+
+// RateLimitKeyFunc extracts the key of the caller (eg. tenant, API key or client IP) from the context.
+// Calls with the same key share a token bucket.
+type RateLimitKeyFunc func(ctx context.Context) string
+
+// ContextValueKey returns a RateLimitKeyFunc that extracts a string value from the context
+// (eg. kithttp.ContextKeyRequestRemoteAddr populated by kithttp.PopulateRequestContext).
+func ContextValueKey(key interface{}) RateLimitKeyFunc {
+	return func(ctx context.Context) string {
+		value, _ := ctx.Value(key).(string)
+
+		return value
+	}
+}
+
+// RateLimitOption configures RateLimitMiddleware.
+type RateLimitOption interface {
+	apply(o *rateLimitOptions)
+}
+
+type rateLimitOptions struct {
+	limits  map[string]RateLimit
+	keyFunc RateLimitKeyFunc
+	store   RateLimitStore
+}
+
+type rateLimitOptionFunc func(o *rateLimitOptions)
+
+func (fn rateLimitOptionFunc) apply(o *rateLimitOptions) {
+	fn(o)
+}
+
+// RateLimitFor overrides the default rate limit for an operation.
+// A zero rate disables rate limiting for the operation.
+func RateLimitFor(name string, limit RateLimit) RateLimitOption {
+	return rateLimitOptionFunc(func(o *rateLimitOptions) { o.limits[name] = limit })
+}
+
+// RateLimitKey sets the function extracting the key of the caller from the context.
+//
+// By default, every caller shares the same token bucket per operation.
+func RateLimitKey(keyFunc RateLimitKeyFunc) RateLimitOption {
+	return rateLimitOptionFunc(func(o *rateLimitOptions) { o.keyFunc = keyFunc })
+}
+
+// RateLimitStorage sets the store holding the token buckets.
+//
+// By default, token buckets are stored in memory (see NewMemoryRateLimitStore).
+func RateLimitStorage(store RateLimitStore) RateLimitOption {
+	return rateLimitOptionFunc(func(o *rateLimitOptions) { o.store = store })
+}
+
+// RateLimitMiddleware returns a MiddlewareFactory that limits the rate of calls per operation and caller
+// using token buckets.
+// A zero default rate only limits operations overridden by RateLimitFor
+// (the factory returns nil for other operations, see MiddlewareFactory).
+//
+// Rejected calls return a *RateLimitError. Errors returned by the store are returned as is.
+func RateLimitMiddleware(limit RateLimit, opts ...RateLimitOption) MiddlewareFactory {
+	o := rateLimitOptions{
+		limits: map[string]RateLimit{},
+	}
+
+	for _, opt := range opts {
+		opt.apply(&o)
+	}
+
+	if o.store == nil {
+		o.store = NewMemoryRateLimitStore()
+	}
+
+	return NameMiddlewareFactory("rate_limit", func(name string) endpoint.Middleware {
+		limit := limit
+		if l, ok := o.limits[name]; ok {
+			limit = l
+		}
+
+		if limit.Rate <= 0 {
+			return nil
+		}
+
+		if limit.Burst < 1 {
+			limit.Burst = 1
+		}
+
+		return func(next endpoint.Endpoint) endpoint.Endpoint {
+			return func(ctx context.Context, request interface{}) (interface{}, error) {
+				key := name
+				if o.keyFunc != nil {
+					key = name + "\x00" + o.keyFunc(ctx)
+				}
+
+				retryAfter, err := o.store.Take(ctx, key, limit)
+				if err != nil {
+					return nil, err
+				}
+
+				if retryAfter > 0 {
+					return nil, &RateLimitError{Operation: name, RetryAfter: retryAfter}
+				}
+
+				return next(ctx, request)
+			}
+		}
+	})
+}
+
+// memoryRateLimitStore holds token buckets in memory.
+type memoryRateLimitStore struct {
+	mu      sync.Mutex
+	buckets map[string]*tokenBucket
+	takes   int
+}
+
+// NewMemoryRateLimitStore returns a RateLimitStore holding token buckets in memory.
+//
+// Idle buckets are removed periodically, so the number of keys does not grow indefinitely.
+func NewMemoryRateLimitStore() RateLimitStore {
+	return &memoryRateLimitStore{
+		buckets: map[string]*tokenBucket{},
+	}
+}
+
+// sweepInterval is the number of takes between removing idle buckets.
+const sweepInterval = 1024
+
+func (s *memoryRateLimitStore) Take(_ context.Context, key string, limit RateLimit) (time.Duration, error) {
+	now := time.Now()
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.takes++
+	if s.takes%sweepInterval == 0 {
+		s.sweep(now)
+	}
+
+	bucket, ok := s.buckets[key]
+	if !ok {
+		bucket = &tokenBucket{tokens: float64(limit.Burst), last: now}
+		s.buckets[key] = bucket
+	}
+
+	return bucket.take(now, limit), nil
+}
+
+// sweep removes buckets that are full (ie. equivalent to a new bucket).
+func (s *memoryRateLimitStore) sweep(now time.Time) {
+	for key, bucket := range s.buckets {
+		if bucket.full(now) {
+			delete(s.buckets, key)
+		}
+	}
+}
+
+// tokenBucket is a token bucket refilled lazily.
+type tokenBucket struct {
+	tokens float64
+	last   time.Time
+	limit  RateLimit
+}
+
+func (b *tokenBucket) refill(now time.Time, limit RateLimit) {
+	b.limit = limit
+
+	if elapsed := now.Sub(b.last); elapsed > 0 {
+		b.tokens = math.Min(float64(limit.Burst), b.tokens+elapsed.Seconds()*limit.Rate)
+		b.last = now
+	}
+}
+
+func (b *tokenBucket) take(now time.Time, limit RateLimit) time.Duration {
+	b.refill(now, limit)
+
+	if b.tokens >= 1 {
+		b.tokens--
+
+		return 0
+	}
+
+	return max(time.Duration((1-b.tokens)/limit.Rate*float64(time.Second)), time.Nanosecond)
+}
+
+func (b *tokenBucket) full(now time.Time) bool {
+	b.refill(now, b.limit)
+
+	return b.tokens >= float64(b.limit.Burst)
+}
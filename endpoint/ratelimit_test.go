@@ -0,0 +1,100 @@
+package endpoint
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+type rateLimitKeyContextKey struct{}
+
+func TestRateLimitMiddleware(t *testing.T) {
+	mf := RateLimitMiddleware(
+		RateLimit{Rate: 1, Burst: 2},
+		RateLimitKey(ContextValueKey(rateLimitKeyContextKey{})),
+		RateLimitFor("report", RateLimit{}),
+	)
+
+	e := mf("greet")(func(context.Context, interface{}) (interface{}, error) { return "response", nil })
+
+	ctx := context.WithValue(context.Background(), rateLimitKeyContextKey{}, "tenant1")
+
+	for i := 0; i < 2; i++ {
+		if _, err := e(ctx, nil); err != nil {
+			t.Fatalf("unexpected error (call %d): %v", i+1, err)
+		}
+	}
+
+	_, err := e(ctx, nil)
+
+	var rateLimitErr *RateLimitError
+	if !errors.As(err, &rateLimitErr) {
+		t.Fatalf("unexpected error\nexpected: %T\nactual:   %v", rateLimitErr, err)
+	}
+
+	if want, have := "greet", rateLimitErr.Operation; want != have {
+		t.Errorf("unexpected operation\nexpected: %s\nactual:   %s", want, have)
+	}
+
+	if rateLimitErr.RetryAfter <= 0 || rateLimitErr.RetryAfter > time.Second {
+		t.Errorf("retry after is out of range: %s", rateLimitErr.RetryAfter)
+	}
+
+	// Other callers have their own bucket
+	if _, err := e(context.WithValue(context.Background(), rateLimitKeyContextKey{}, "tenant2"), nil); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+
+	if mf("report") != nil {
+		t.Error("rate limiting is expected to be disabled")
+	}
+}
+
+type rateLimitStoreStub struct {
+	keys []string
+	err  error
+}
+
+func (s *rateLimitStoreStub) Take(_ context.Context, key string, _ RateLimit) (time.Duration, error) {
+	s.keys = append(s.keys, key)
+
+	return 0, s.err
+}
+
+func TestRateLimitMiddleware_Store(t *testing.T) {
+	store := &rateLimitStoreStub{err: errors.New("store error")}
+
+	e := RateLimitMiddleware(
+		RateLimit{Rate: 1, Burst: 1},
+		RateLimitStorage(store),
+	)("greet")(func(context.Context, interface{}) (interface{}, error) { return "response", nil })
+
+	if _, err := e(context.Background(), nil); !errors.Is(err, store.err) {
+		t.Errorf("unexpected error: %v", err)
+	}
+
+	if want, have := 1, len(store.keys); want != have || store.keys[0] != "greet" {
+		t.Errorf("unexpected keys: %v", store.keys)
+	}
+}
+
+func TestMemoryRateLimitStore(t *testing.T) {
+	store := NewMemoryRateLimitStore()
+	limit := RateLimit{Rate: 100, Burst: 1}
+
+	if retryAfter, _ := store.Take(context.Background(), "key", limit); retryAfter != 0 {
+		t.Fatalf("unexpected retry after: %s", retryAfter)
+	}
+
+	retryAfter, _ := store.Take(context.Background(), "key", limit)
+	if retryAfter <= 0 {
+		t.Fatal("token is not expected to be available")
+	}
+
+	time.Sleep(retryAfter)
+
+	if retryAfter, _ := store.Take(context.Background(), "key", limit); retryAfter != 0 {
+		t.Errorf("token is expected to be available after %s", retryAfter)
+	}
+}
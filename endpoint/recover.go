@@ -0,0 +1,71 @@
+package endpoint
+
+import (
+	"context"
+	"fmt"
+	"runtime/debug"
+
+	"github.com/go-kit/kit/endpoint"
+
+	"github.com/sagikazarmark/kitx/transport"
+)
+
+// PanicError is returned by RecoverMiddleware when an endpoint panics.
+type PanicError struct {
+	// Operation is the name of the operation that panicked.
+	Operation string
+
+	// Value is the value passed to panic.
+	Value interface{}
+
+	// Stack is the stack trace of the goroutine at the time of the panic.
+	Stack []byte
+}
+
+// Error implements the error interface.
+func (e *PanicError) Error() string {
+	return fmt.Sprintf("panic in operation %q: %v", e.Operation, e.Value)
+}
+
+// Unwrap returns the panic value if it is an error.
+func (e *PanicError) Unwrap() error {
+	err, _ := e.Value.(error)
+
+	return err
+}
+
+// RecoverMiddleware returns a MiddlewareFactory that recovers from panics in endpoints
+// and turns them into a *PanicError.
+//
+// The error is reported to the error handler (if any) before it is returned.
+// If the error handler implements transport.ErrorHandlerContext, it receives the context of the call.
+func RecoverMiddleware(errorHandler transport.ErrorHandler) MiddlewareFactory {
+	return NameMiddlewareFactory("recover", func(name string) endpoint.Middleware {
+		return func(next endpoint.Endpoint) endpoint.Endpoint {
+			return func(ctx context.Context, request interface{}) (response interface{}, err error) {
+				defer func() {
+					v := recover()
+					if v == nil {
+						return
+					}
+
+					panicErr := &PanicError{
+						Operation: name,
+						Value:     v,
+						Stack:     debug.Stack(),
+					}
+
+					if h, ok := errorHandler.(transport.ErrorHandlerContext); ok {
+						h.HandleContext(ctx, panicErr)
+					} else if errorHandler != nil {
+						errorHandler.Handle(panicErr)
+					}
+
+					response, err = nil, panicErr
+				}()
+
+				return next(ctx, request)
+			}
+		}
+	})
+}
@@ -0,0 +1,89 @@
+package endpoint
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+)
+
+type errorHandlerStub struct {
+	ctx  context.Context
+	errs []error
+}
+
+func (h *errorHandlerStub) Handle(err error) {
+	h.errs = append(h.errs, err)
+}
+
+func (h *errorHandlerStub) HandleContext(ctx context.Context, err error) {
+	h.ctx = ctx
+	h.Handle(err)
+}
+
+func TestRecoverMiddleware(t *testing.T) {
+	errorHandler := &errorHandlerStub{}
+
+	e := RecoverMiddleware(errorHandler)("greet")(func(context.Context, interface{}) (interface{}, error) {
+		panic("something went wrong")
+	})
+
+	ctx := context.WithValue(context.Background(), contextKey("key"), "value")
+
+	response, err := e(ctx, nil)
+	if response != nil {
+		t.Errorf("unexpected response: %v", response)
+	}
+
+	var panicErr *PanicError
+	if !errors.As(err, &panicErr) {
+		t.Fatalf("unexpected error\nexpected: %T\nactual:   %v", panicErr, err)
+	}
+
+	if want, have := "greet", panicErr.Operation; want != have {
+		t.Errorf("unexpected operation\nexpected: %s\nactual:   %s", want, have)
+	}
+
+	if want, have := "something went wrong", panicErr.Value; want != have {
+		t.Errorf("unexpected panic value\nexpected: %v\nactual:   %v", want, have)
+	}
+
+	if !strings.Contains(string(panicErr.Stack), "TestRecoverMiddleware") {
+		t.Errorf("stack trace is expected to contain the panicking function:\n%s", panicErr.Stack)
+	}
+
+	if want, have := 1, len(errorHandler.errs); want != have || errorHandler.errs[0] != err {
+		t.Errorf("unexpected reported errors: %v", errorHandler.errs)
+	}
+
+	if errorHandler.ctx != ctx {
+		t.Error("error handler is expected to receive the context of the call")
+	}
+}
+
+func TestRecoverMiddleware_Error(t *testing.T) {
+	perr := errors.New("error")
+
+	e := RecoverMiddleware(nil)("greet")(func(context.Context, interface{}) (interface{}, error) {
+		panic(perr)
+	})
+
+	if _, err := e(context.Background(), nil); !errors.Is(err, perr) {
+		t.Errorf("panic error is expected to wrap the panic value: %v", err)
+	}
+}
+
+func TestRecoverMiddleware_NoPanic(t *testing.T) {
+	e := RecoverMiddleware(nil)("greet")(func(context.Context, interface{}) (interface{}, error) {
+		return "response", nil
+	})
+
+	response, err := e(context.Background(), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if want, have := "response", response; want != have {
+		t.Errorf("unexpected response\nexpected: %v\nactual:   %v", want, have)
+	}
+}
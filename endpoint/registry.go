@@ -0,0 +1,111 @@
+package endpoint
+
+import (
+	"sort"
+	"sync"
+)
+
+// OperationMetadata is user-supplied information about an operation.
+type OperationMetadata struct {
+	// Description is a human readable description of the operation.
+	Description string `json:"description,omitempty"`
+
+	// Tags group operations (eg. by domain or API version).
+	Tags []string `json:"tags,omitempty"`
+
+	// Deprecated marks the operation as deprecated.
+	Deprecated bool `json:"deprecated,omitempty"`
+
+	// DeprecationMessage explains why the operation is deprecated and what to use instead.
+	DeprecationMessage string `json:"deprecationMessage,omitempty"`
+}
+
+// Operation describes an operation created by a Factory.
+type Operation struct {
+	// Name is the name of the operation.
+	Name string `json:"name"`
+
+	// Middleware is the list of middleware applied to the operation (in order).
+	// See Describer for how middleware is named.
+	Middleware []string `json:"middleware"`
+
+	OperationMetadata
+}
+
+// Registry records operations created by a Factory.
+//
+// It is safe for concurrent use.
+type Registry struct {
+	mu         sync.RWMutex
+	middleware map[string][]string
+	metadata   map[string]OperationMetadata
+}
+
+// NewRegistry returns a new Registry.
+func NewRegistry() *Registry {
+	return &Registry{
+		middleware: map[string][]string{},
+		metadata:   map[string]OperationMetadata{},
+	}
+}
+
+// SetMetadata attaches metadata to an operation.
+// It can be called before or after the operation is created.
+// Metadata of operations that are never created is not returned.
+func (r *Registry) SetMetadata(name string, metadata OperationMetadata) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	metadata.Tags = append([]string(nil), metadata.Tags...)
+
+	r.metadata[name] = metadata
+}
+
+// register records the middleware applied to an operation.
+func (r *Registry) register(name string, middleware []string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.middleware[name] = append([]string{}, middleware...)
+}
+
+// Operation returns an operation by name.
+func (r *Registry) Operation(name string) (Operation, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	if _, ok := r.middleware[name]; !ok {
+		return Operation{}, false
+	}
+
+	return r.operation(name), true
+}
+
+// Operations returns every recorded operation sorted by name.
+func (r *Registry) Operations() []Operation {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	operations := make([]Operation, 0, len(r.middleware))
+	for name := range r.middleware {
+		operations = append(operations, r.operation(name))
+	}
+
+	sort.Slice(operations, func(i, j int) bool {
+		return operations[i].Name < operations[j].Name
+	})
+
+	return operations
+}
+
+// operation returns a copy of a registered operation, so callers cannot modify the registry.
+func (r *Registry) operation(name string) Operation {
+	metadata := r.metadata[name]
+	metadata.Tags = append([]string(nil), metadata.Tags...)
+
+	return Operation{
+		Name:              name,
+		Middleware:        append([]string{}, r.middleware[name]...),
+		OperationMetadata: metadata,
+	}
+}
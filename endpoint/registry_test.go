@@ -0,0 +1,114 @@
+package endpoint
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/go-kit/kit/endpoint"
+	"github.com/go-kit/log"
+)
+
+func TestFactoryRegistry(t *testing.T) {
+	registry := NewRegistry()
+
+	registry.SetMetadata("greet", OperationMetadata{Description: "Greets the caller", Tags: []string{"public"}})
+
+	f := NewFactoryWithOptions(
+		FactoryNamedMiddleware("logging", func(string) endpoint.Middleware { return Combine() }),
+		FactoryNamedMiddleware("auth", Exclude(MatchGlob("health"), func(string) endpoint.Middleware { return Combine() })),
+		FactoryRegistry(registry),
+	)
+
+	e := func(context.Context, interface{}) (interface{}, error) { return nil, nil }
+
+	f.NewEndpoint("health", e)
+	f.NewEndpoint("greet", e)
+
+	registry.SetMetadata("health", OperationMetadata{Deprecated: true, DeprecationMessage: "use the gRPC health service"})
+	registry.SetMetadata("unknown", OperationMetadata{Description: "Never created"})
+
+	expected := []Operation{
+		{
+			Name:              "greet",
+			Middleware:        []string{"logging", "auth"},
+			OperationMetadata: OperationMetadata{Description: "Greets the caller", Tags: []string{"public"}},
+		},
+		{
+			Name:              "health",
+			Middleware:        []string{"logging"},
+			OperationMetadata: OperationMetadata{Deprecated: true, DeprecationMessage: "use the gRPC health service"},
+		},
+	}
+
+	if want, have := expected, registry.Operations(); !reflect.DeepEqual(want, have) {
+		t.Errorf("unexpected operations\nexpected: %+v\nactual:   %+v", want, have)
+	}
+
+	if _, ok := registry.Operation("unknown"); ok {
+		t.Error("unknown operation is not expected to be found")
+	}
+}
+
+func TestRegistry_Copies(t *testing.T) {
+	registry := NewRegistry()
+
+	registry.SetMetadata("greet", OperationMetadata{Tags: []string{"public"}})
+	registry.register("greet", []string{"logging"})
+
+	op, _ := registry.Operation("greet")
+	op.Tags[0] = "private"
+	op.Middleware[0] = "auth"
+
+	op, _ = registry.Operation("greet")
+
+	if want, have := "public", op.Tags[0]; want != have {
+		t.Errorf("unexpected tag\nexpected: %s\nactual:   %s", want, have)
+	}
+
+	if want, have := "logging", op.Middleware[0]; want != have {
+		t.Errorf("unexpected middleware\nexpected: %s\nactual:   %s", want, have)
+	}
+}
+
+func TestFactoryRegistry_MiddlewareNames(t *testing.T) {
+	registry := NewRegistry()
+
+	nop := func(string) endpoint.Middleware { return Combine() }
+
+	f := NewFactoryWithOptions(
+		FactoryMiddleware(
+			LoggingMiddleware(log.NewNopLogger()),
+			RecoverMiddleware(nil),
+			TimeoutMiddleware(time.Second),
+			nop,
+			NameMiddlewareFactory("custom", func(string) endpoint.Middleware { return Combine() }),
+		),
+		FactoryRegistry(registry),
+	)
+
+	f.NewEndpoint("greet", func(context.Context, interface{}) (interface{}, error) { return nil, nil })
+
+	op, _ := registry.Operation("greet")
+
+	expected := []string{"logging", "recover", "timeout", "middleware#3", "custom"}
+	if want, have := expected, op.Middleware; !reflect.DeepEqual(want, have) {
+		t.Errorf("unexpected middleware\nexpected: %v\nactual:   %v", want, have)
+	}
+}
+
+func TestNameMiddlewareFactory_Conflict(t *testing.T) {
+	m := func(e endpoint.Endpoint) endpoint.Endpoint { return e }
+
+	first := NameMiddlewareFactory("first", Middleware(m))
+	second := NameMiddlewareFactory("second", Middleware(m))
+
+	if want, have := "", middlewareFactoryName(first); want != have {
+		t.Errorf("unexpected name\nexpected: %q\nactual:   %q", want, have)
+	}
+
+	if want, have := "", middlewareFactoryName(second); want != have {
+		t.Errorf("unexpected name\nexpected: %q\nactual:   %q", want, have)
+	}
+}
@@ -0,0 +1,248 @@
+package endpoint
+
+import (
+	"context"
+	"math/rand/v2"
+	"sync/atomic"
+	"time"
+
+	"github.com/go-kit/kit/endpoint"
+)
+
+// Backoff returns the delay before a retry attempt.
+// The first retry is the second attempt (attempt number 2).
+type Backoff func(attempt int) time.Duration
+
+// ConstantBackoff returns a Backoff that always waits the same amount of time.
+func ConstantBackoff(delay time.Duration) Backoff {
+	return func(_ int) time.Duration {
+		return delay
+	}
+}
+
+// ExponentialBackoff returns a Backoff that doubles the delay after every attempt (starting from initial),
+// up to maxDelay.
+func ExponentialBackoff(initial time.Duration, maxDelay time.Duration) Backoff {
+	return func(attempt int) time.Duration {
+		delay := initial
+
+		for i := 2; i < attempt && delay < maxDelay; i++ {
+			delay *= 2
+		}
+
+		if delay > maxDelay {
+			return maxDelay
+		}
+
+		return delay
+	}
+}
+
+// FullJitter randomizes the delay returned by a Backoff between zero and the original delay.
+//
+// See https://aws.amazon.com/blogs/architecture/exponential-backoff-and-jitter/
+func FullJitter(backoff Backoff) Backoff {
+	return func(attempt int) time.Duration {
+		delay := backoff(attempt)
+		if delay <= 0 {
+			return 0
+		}
+
+		return rand.N(delay) // nolint: gosec
+	}
+}
+
+// RetryOption configures RetryMiddleware.
+type RetryOption interface {
+	apply(o *retryOptions)
+}
+
+type retryOptions struct {
+	maxAttempts int
+	backoff     Backoff
+	budget      time.Duration
+	retryFailed bool
+	notify      func(ctx context.Context, attempt int, err error)
+}
+
+type retryOptionFunc func(o *retryOptions)
+
+func (fn retryOptionFunc) apply(o *retryOptions) {
+	fn(o)
+}
+
+// RetryMaxAttempts sets the maximum number of attempts (including the first one).
+//
+// By default, an endpoint is called at most 3 times.
+func RetryMaxAttempts(attempts int) RetryOption {
+	return retryOptionFunc(func(o *retryOptions) { o.maxAttempts = attempts })
+}
+
+// RetryBackoff sets the backoff policy.
+//
+// By default, exponential backoff with full jitter is used (starting from 100ms, up to 5s).
+func RetryBackoff(backoff Backoff) RetryOption {
+	return retryOptionFunc(func(o *retryOptions) { o.backoff = backoff })
+}
+
+// RetryBudget sets the total time budget of the attempts (including the backoff delays).
+// The attempts are called with a context whose deadline is the end of the budget,
+// and no further attempts are made if the next one would start after the budget is exhausted.
+//
+// By default, there is no time budget (other than the deadline of the context).
+func RetryBudget(budget time.Duration) RetryOption {
+	return retryOptionFunc(func(o *retryOptions) { o.budget = budget })
+}
+
+// RetryFailed enables retrying failed responses (see endpoint.Failer) matching the ErrorMatcher.
+func RetryFailed() RetryOption {
+	return retryOptionFunc(func(o *retryOptions) { o.retryFailed = true })
+}
+
+// RetryNotify registers a function called before every retry with the attempt number and the error of the previous attempt.
+func RetryNotify(fn func(ctx context.Context, attempt int, err error)) RetryOption {
+	return retryOptionFunc(func(o *retryOptions) { o.notify = fn })
+}
+
+// RetryMiddleware returns a MiddlewareFactory that retries endpoint calls returning errors matching the ErrorMatcher.
+// A nil ErrorMatcher matches every error.
+//
+// Retries stop when the context is canceled: the result of the last attempt is returned.
+//
+// The number of attempts is recorded in the context of middleware (eg. LoggingMiddleware or MetricsMiddleware)
+// wrapping the retry middleware of the same operation (see Attempts).
+// The current attempt number is available in the context passed to the endpoint (see Attempt).
+func RetryMiddleware(errorMatcher ErrorMatcher, opts ...RetryOption) MiddlewareFactory {
+	o := retryOptions{
+		maxAttempts: 3,
+		backoff:     FullJitter(ExponentialBackoff(100*time.Millisecond, 5*time.Second)),
+	}
+
+	for _, opt := range opts {
+		opt.apply(&o)
+	}
+
+	if errorMatcher == nil {
+		errorMatcher = func(error) bool { return true }
+	}
+
+	// retryableError returns the error of an attempt if it should be retried
+	retryableError := func(response interface{}, err error) error {
+		if !o.retryFailed && err == nil {
+			return nil
+		}
+
+		if err := callError(response, err); err != nil && errorMatcher(err) {
+			return err
+		}
+
+		return nil
+	}
+
+	return NameMiddlewareFactory("retry", func(name string) endpoint.Middleware {
+		return func(next endpoint.Endpoint) endpoint.Endpoint {
+			return func(ctx context.Context, request interface{}) (interface{}, error) {
+				var deadline time.Time
+				if o.budget > 0 {
+					deadline = time.Now().Add(o.budget)
+
+					var cancel context.CancelFunc
+
+					ctx, cancel = context.WithDeadline(ctx, deadline)
+					defer cancel()
+				}
+
+				counter := attemptCounterFromContext(ctx, name)
+
+				for attempt := 1; ; attempt++ {
+					if counter != nil {
+						counter.n.Store(int64(attempt))
+					}
+
+					response, err := next(context.WithValue(ctx, attemptContextKey, attempt), request)
+
+					retryErr := retryableError(response, err)
+					if retryErr == nil || attempt >= o.maxAttempts || ctx.Err() != nil {
+						return response, err
+					}
+
+					delay := o.backoff(attempt + 1)
+
+					if !deadline.IsZero() && time.Now().Add(delay).After(deadline) {
+						return response, err
+					}
+
+					if o.notify != nil {
+						o.notify(ctx, attempt+1, retryErr)
+					}
+
+					timer := time.NewTimer(delay)
+
+					select {
+					case <-ctx.Done():
+						timer.Stop()
+
+						return response, err
+
+					case <-timer.C:
+					}
+				}
+			}
+		}
+	})
+}
+
+// attemptContextKey holds the key used to store the current attempt number in the context.
+const attemptContextKey contextKey = "attempt"
+
+// Attempt returns the current attempt number of an endpoint call retried by RetryMiddleware (starting from 1).
+func Attempt(ctx context.Context) (int, bool) {
+	attempt, ok := ctx.Value(attemptContextKey).(int)
+
+	return attempt, ok
+}
+
+// attemptCounterContextKey holds the key used to store an attempt counter in the context.
+const attemptCounterContextKey contextKey = "attemptCounter"
+
+// attemptCounter records the number of attempts made by RetryMiddleware for an operation.
+type attemptCounter struct {
+	operation string
+	n         atomic.Int64
+}
+
+// WithAttemptCounter returns a context that records the number of attempts made by RetryMiddleware
+// for an operation down the middleware chain.
+// It can be used in middleware wrapping RetryMiddleware to report attempt counts (see Attempts).
+//
+// If the context already holds an attempt counter for the operation, it is returned as is,
+// so every middleware in a chain observes the same counter.
+// Counters of other operations (eg. of a server endpoint calling a client endpoint) are not affected.
+func WithAttemptCounter(ctx context.Context, operation string) context.Context {
+	if attemptCounterFromContext(ctx, operation) != nil {
+		return ctx
+	}
+
+	return context.WithValue(ctx, attemptCounterContextKey, &attemptCounter{operation: operation})
+}
+
+// Attempts returns the number of attempts made by RetryMiddleware for an operation
+// in a context returned by WithAttemptCounter.
+// It returns zero if no attempts are recorded.
+func Attempts(ctx context.Context, operation string) int {
+	counter := attemptCounterFromContext(ctx, operation)
+	if counter == nil {
+		return 0
+	}
+
+	return int(counter.n.Load())
+}
+
+func attemptCounterFromContext(ctx context.Context, operation string) *attemptCounter {
+	counter, ok := ctx.Value(attemptCounterContextKey).(*attemptCounter)
+	if !ok || counter.operation != operation {
+		return nil
+	}
+
+	return counter
+}
@@ -0,0 +1,293 @@
+package endpoint
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"log/slog"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-kit/kit/endpoint"
+)
+
+var errRetryable = errors.New("retryable")
+
+func isRetryable(err error) bool {
+	return errors.Is(err, errRetryable)
+}
+
+// flakyEndpoint fails with the given errors before returning a successful response.
+func flakyEndpoint(errs ...error) (endpoint.Endpoint, *int) {
+	var calls int
+
+	return func(ctx context.Context, _ interface{}) (interface{}, error) {
+		calls++
+
+		if attempt, _ := Attempt(ctx); attempt != calls {
+			return nil, errors.New("unexpected attempt number")
+		}
+
+		if calls <= len(errs) {
+			return nil, errs[calls-1]
+		}
+
+		return "response", nil
+	}, &calls
+}
+
+func TestRetryMiddleware(t *testing.T) {
+	e, calls := flakyEndpoint(errRetryable, errRetryable)
+
+	var notified []int
+
+	mf := RetryMiddleware(
+		isRetryable,
+		RetryBackoff(ConstantBackoff(0)),
+		RetryNotify(func(_ context.Context, attempt int, _ error) { notified = append(notified, attempt) }),
+	)
+
+	ctx := WithAttemptCounter(context.Background(), "greet")
+
+	response, err := mf("greet")(e)(ctx, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if want, have := "response", response; want != have {
+		t.Errorf("unexpected response\nexpected: %v\nactual:   %v", want, have)
+	}
+
+	if want, have := 3, *calls; want != have {
+		t.Errorf("unexpected number of calls\nexpected: %d\nactual:   %d", want, have)
+	}
+
+	if want, have := 3, Attempts(ctx, "greet"); want != have {
+		t.Errorf("unexpected number of recorded attempts\nexpected: %d\nactual:   %d", want, have)
+	}
+
+	if want, have := 2, len(notified); want != have || notified[0] != 2 || notified[1] != 3 {
+		t.Errorf("unexpected notifications: %v", notified)
+	}
+}
+
+func TestRetryMiddleware_MaxAttempts(t *testing.T) {
+	e, calls := flakyEndpoint(errRetryable, errRetryable, errRetryable)
+
+	_, err := RetryMiddleware(isRetryable, RetryMaxAttempts(2), RetryBackoff(ConstantBackoff(0)))("greet")(e)(context.Background(), nil)
+
+	if !errors.Is(err, errRetryable) {
+		t.Errorf("unexpected error: %v", err)
+	}
+
+	if want, have := 2, *calls; want != have {
+		t.Errorf("unexpected number of calls\nexpected: %d\nactual:   %d", want, have)
+	}
+}
+
+func TestRetryMiddleware_NotRetryable(t *testing.T) {
+	e, calls := flakyEndpoint(errors.New("error"))
+
+	_, err := RetryMiddleware(isRetryable, RetryBackoff(ConstantBackoff(0)))("greet")(e)(context.Background(), nil)
+	if err == nil {
+		t.Fatal("error is expected")
+	}
+
+	if want, have := 1, *calls; want != have {
+		t.Errorf("unexpected number of calls\nexpected: %d\nactual:   %d", want, have)
+	}
+}
+
+func TestRetryMiddleware_Budget(t *testing.T) {
+	e, calls := flakyEndpoint(errRetryable, errRetryable)
+
+	mf := RetryMiddleware(isRetryable, RetryBackoff(ConstantBackoff(time.Hour)), RetryBudget(time.Second))
+
+	_, err := mf("greet")(e)(context.Background(), nil)
+	if !errors.Is(err, errRetryable) {
+		t.Errorf("unexpected error: %v", err)
+	}
+
+	if want, have := 1, *calls; want != have {
+		t.Errorf("unexpected number of calls\nexpected: %d\nactual:   %d", want, have)
+	}
+}
+
+func TestRetryMiddleware_ContextCanceled(t *testing.T) {
+	e, calls := flakyEndpoint(errRetryable, errRetryable)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	_, err := RetryMiddleware(isRetryable, RetryBackoff(ConstantBackoff(time.Hour)))("greet")(e)(ctx, nil)
+	if !errors.Is(err, errRetryable) {
+		t.Errorf("unexpected error: %v", err)
+	}
+
+	if want, have := 1, *calls; want != have {
+		t.Errorf("unexpected number of calls\nexpected: %d\nactual:   %d", want, have)
+	}
+}
+
+func TestRetryMiddleware_Failed(t *testing.T) {
+	var calls int
+
+	e := func(context.Context, interface{}) (interface{}, error) {
+		calls++
+
+		return NewFailure(errRetryable), nil
+	}
+
+	_, _ = RetryMiddleware(isRetryable, RetryBackoff(ConstantBackoff(0)))("greet")(e)(context.Background(), nil)
+
+	if want, have := 1, calls; want != have {
+		t.Errorf("failed responses are not expected to be retried by default\nexpected: %d\nactual:   %d", want, have)
+	}
+
+	calls = 0
+
+	_, _ = RetryMiddleware(isRetryable, RetryBackoff(ConstantBackoff(0)), RetryFailed())("greet")(e)(context.Background(), nil)
+
+	if want, have := 3, calls; want != have {
+		t.Errorf("unexpected number of calls\nexpected: %d\nactual:   %d", want, have)
+	}
+}
+
+func TestExponentialBackoff(t *testing.T) {
+	backoff := ExponentialBackoff(100*time.Millisecond, time.Second)
+
+	expected := []time.Duration{
+		100 * time.Millisecond,
+		200 * time.Millisecond,
+		400 * time.Millisecond,
+		800 * time.Millisecond,
+		time.Second,
+		time.Second,
+	}
+
+	for i, want := range expected {
+		attempt := i + 2
+
+		if have := backoff(attempt); want != have {
+			t.Errorf("unexpected delay for attempt %d\nexpected: %s\nactual:   %s", attempt, want, have)
+		}
+	}
+}
+
+func TestFullJitter(t *testing.T) {
+	backoff := FullJitter(ConstantBackoff(time.Second))
+
+	for i := 0; i < 100; i++ {
+		if delay := backoff(2); delay < 0 || delay >= time.Second {
+			t.Fatalf("delay is out of range: %s", delay)
+		}
+	}
+}
+
+func TestRetryMiddleware_StackedAttemptCounters(t *testing.T) {
+	var buf bytes.Buffer
+
+	logger := slog.New(slog.NewTextHandler(&buf, nil))
+	retries := newLabeledCounter()
+
+	factory := NewFactory(
+		SlogLoggingMiddleware(logger),
+		MetricsMiddleware(Metrics{Retries: retries}),
+		RetryMiddleware(isRetryable, RetryBackoff(ConstantBackoff(0))),
+	)
+
+	e, _ := flakyEndpoint(errRetryable, errRetryable)
+
+	if _, err := factory.NewEndpoint("greet", e)(context.Background(), nil); err != nil {
+		t.Fatal(err)
+	}
+
+	if want, have := 2.0, retries.value(OperationLabel, "greet"); want != have {
+		t.Errorf("unexpected retry count\nexpected: %v\nactual:   %v", want, have)
+	}
+
+	if want, have := "attempts=3", buf.String(); !strings.Contains(have, want) {
+		t.Errorf("log output does not contain the expected value\nexpected: %s\nactual:   %s", want, have)
+	}
+}
+
+func TestWithAttemptCounter_Reuse(t *testing.T) {
+	ctx := WithAttemptCounter(context.Background(), "greet")
+
+	if WithAttemptCounter(ctx, "greet") != ctx {
+		t.Error("an existing attempt counter is supposed to be reused")
+	}
+
+	if WithAttemptCounter(ctx, "other") == ctx {
+		t.Error("an attempt counter of another operation is not supposed to be reused")
+	}
+}
+
+func TestRetryMiddleware_NestedOperations(t *testing.T) {
+	var buf bytes.Buffer
+
+	logger := slog.New(slog.NewTextHandler(&buf, nil))
+
+	factory := NewFactory(
+		SlogLoggingMiddleware(logger),
+		RetryMiddleware(isRetryable, RetryBackoff(ConstantBackoff(0))),
+	)
+
+	// A client endpoint retried by its own middleware
+	inner, _ := flakyEndpoint(errRetryable, errRetryable)
+	inner = factory.NewEndpoint("inner", inner)
+
+	// A server endpoint calling the client endpoint
+	outer := factory.NewEndpoint("outer", func(ctx context.Context, request interface{}) (interface{}, error) {
+		return inner(ctx, request)
+	})
+
+	if _, err := outer(context.Background(), nil); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, line := range strings.Split(strings.TrimSpace(buf.String()), "\n") {
+		switch {
+		case strings.Contains(line, "operation=inner"):
+			if !strings.Contains(line, "attempts=3") {
+				t.Errorf("inner log line is supposed to contain the number of attempts: %s", line)
+			}
+
+		case strings.Contains(line, "operation=outer"):
+			if strings.Contains(line, "attempts=") {
+				t.Errorf("outer log line is not supposed to contain the attempts of the inner operation: %s", line)
+			}
+
+		default:
+			t.Errorf("unexpected log line: %s", line)
+		}
+	}
+}
+
+func TestRetryMiddleware_BudgetDeadline(t *testing.T) {
+	e := func(ctx context.Context, _ interface{}) (interface{}, error) {
+		<-ctx.Done()
+
+		return nil, ctx.Err()
+	}
+
+	mf := RetryMiddleware(nil, RetryBudget(20*time.Millisecond), RetryBackoff(ConstantBackoff(0)))
+
+	done := make(chan error, 1)
+
+	go func() {
+		_, err := mf("greet")(e)(context.Background(), nil)
+		done <- err
+	}()
+
+	select {
+	case err := <-done:
+		if !errors.Is(err, context.DeadlineExceeded) {
+			t.Errorf("unexpected error\nexpected: %v\nactual:   %v", context.DeadlineExceeded, err)
+		}
+
+	case <-time.After(time.Second):
+		t.Fatal("the retry budget is supposed to cancel the running attempt")
+	}
+}
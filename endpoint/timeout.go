@@ -0,0 +1,105 @@
+package endpoint
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/go-kit/kit/endpoint"
+)
+
+// TimeoutError is returned by TimeoutMiddleware when the deadline of an operation is exceeded.
+type TimeoutError struct {
+	// Operation is the name of the operation that timed out.
+	Operation string
+
+	// Duration is the timeout applied to the operation.
+	Duration time.Duration
+}
+
+// Error implements the error interface.
+func (e *TimeoutError) Error() string {
+	return fmt.Sprintf("operation %q timed out after %s", e.Operation, e.Duration)
+}
+
+// Unwrap returns context.DeadlineExceeded, so the error can be checked using errors.Is.
+func (*TimeoutError) Unwrap() error {
+	return context.DeadlineExceeded
+}
+
+// Timeout implements the timeout behavior.
+func (*TimeoutError) Timeout() bool {
+	return true
+}
+
+// TimeoutOption configures TimeoutMiddleware.
+type TimeoutOption interface {
+	apply(o *timeoutOptions)
+}
+
+type timeoutOptions struct {
+	timeouts map[string]time.Duration
+}
+
+type timeoutOptionFunc func(o *timeoutOptions)
+
+func (fn timeoutOptionFunc) apply(o *timeoutOptions) {
+	fn(o)
+}
+
+// TimeoutFor overrides the default timeout for an operation.
+// A zero or negative timeout disables the timeout for the operation.
+func TimeoutFor(name string, timeout time.Duration) TimeoutOption {
+	return timeoutOptionFunc(func(o *timeoutOptions) { o.timeouts[name] = timeout })
+}
+
+// TimeoutMiddleware returns a MiddlewareFactory that applies a deadline to every endpoint call.
+// A zero or negative default timeout only applies timeouts to operations overridden by TimeoutFor
+// (the factory returns nil for other operations, see MiddlewareFactory).
+//
+// The deadline of the caller's context is never extended:
+// if it expires earlier than the operation timeout, the timeout is not applied.
+//
+// When the operation deadline is exceeded, the error returned by the endpoint is replaced with a *TimeoutError.
+// Note: the endpoint is expected to honor context cancellation.
+func TimeoutMiddleware(timeout time.Duration, opts ...TimeoutOption) MiddlewareFactory {
+	o := timeoutOptions{
+		timeouts: map[string]time.Duration{},
+	}
+
+	for _, opt := range opts {
+		opt.apply(&o)
+	}
+
+	return NameMiddlewareFactory("timeout", func(name string) endpoint.Middleware {
+		timeout := timeout
+		if t, ok := o.timeouts[name]; ok {
+			timeout = t
+		}
+
+		if timeout <= 0 {
+			return nil
+		}
+
+		return func(next endpoint.Endpoint) endpoint.Endpoint {
+			return func(ctx context.Context, request interface{}) (interface{}, error) {
+				deadline := time.Now().Add(timeout)
+
+				if d, ok := ctx.Deadline(); ok && !d.After(deadline) {
+					return next(ctx, request)
+				}
+
+				tctx, cancel := context.WithDeadline(ctx, deadline)
+				defer cancel()
+
+				response, err := next(tctx, request)
+				if err != nil && errors.Is(tctx.Err(), context.DeadlineExceeded) && ctx.Err() == nil {
+					return response, &TimeoutError{Operation: name, Duration: timeout}
+				}
+
+				return response, err
+			}
+		}
+	})
+}
@@ -0,0 +1,85 @@
+package endpoint
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func blockingEndpoint(ctx context.Context, _ interface{}) (interface{}, error) {
+	<-ctx.Done()
+
+	return nil, ctx.Err()
+}
+
+func TestTimeoutMiddleware(t *testing.T) {
+	e := TimeoutMiddleware(10 * time.Millisecond)("greet")(blockingEndpoint)
+
+	_, err := e(context.Background(), nil)
+
+	var timeoutErr *TimeoutError
+	if !errors.As(err, &timeoutErr) {
+		t.Fatalf("unexpected error\nexpected: %T\nactual:   %v", timeoutErr, err)
+	}
+
+	if want, have := "greet", timeoutErr.Operation; want != have {
+		t.Errorf("unexpected operation\nexpected: %s\nactual:   %s", want, have)
+	}
+
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Error("timeout error is expected to match context.DeadlineExceeded")
+	}
+}
+
+func TestTimeoutMiddleware_Override(t *testing.T) {
+	mf := TimeoutMiddleware(time.Hour, TimeoutFor("greet", 10*time.Millisecond), TimeoutFor("report", 0))
+
+	var timeoutErr *TimeoutError
+
+	_, err := mf("greet")(blockingEndpoint)(context.Background(), nil)
+	if !errors.As(err, &timeoutErr) || timeoutErr.Duration != 10*time.Millisecond {
+		t.Errorf("unexpected error: %v", err)
+	}
+
+	if mf("report") != nil {
+		t.Error("timeout middleware is expected to be disabled")
+	}
+}
+
+func TestTimeoutMiddleware_CallerDeadline(t *testing.T) {
+	t.Run("earlier", func(t *testing.T) {
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+		defer cancel()
+
+		_, err := TimeoutMiddleware(time.Hour)("greet")(blockingEndpoint)(ctx, nil)
+
+		var timeoutErr *TimeoutError
+		if errors.As(err, &timeoutErr) {
+			t.Error("caller deadline is not expected to be reported as a timeout error")
+		}
+
+		if !errors.Is(err, context.DeadlineExceeded) {
+			t.Errorf("unexpected error: %v", err)
+		}
+	})
+
+	t.Run("later", func(t *testing.T) {
+		ctx, cancel := context.WithTimeout(context.Background(), time.Hour)
+		defer cancel()
+
+		callerDeadline, _ := ctx.Deadline()
+
+		e := TimeoutMiddleware(time.Minute)("greet")(func(ctx context.Context, _ interface{}) (interface{}, error) {
+			deadline, _ := ctx.Deadline()
+
+			if !deadline.Before(callerDeadline) {
+				t.Error("deadline is expected to be shortened")
+			}
+
+			return nil, nil
+		})
+
+		_, _ = e(ctx, nil)
+	})
+}
@@ -0,0 +1,119 @@
+package endpoint
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+
+	"github.com/go-kit/kit/endpoint"
+)
+
+// Typed is a type-safe version of endpoint.Endpoint.
+type Typed[Req, Resp any] func(ctx context.Context, request Req) (response Resp, err error)
+
+// Endpoint converts a typed endpoint into a go-kit endpoint.
+//
+// The returned endpoint returns a *TypeError if the request is not of type Req.
+func (e Typed[Req, Resp]) Endpoint() endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req, ok := request.(Req)
+		if !ok {
+			if request != nil || !isNillable[Req]() {
+				return nil, newTypeError[Req]("request", request)
+			}
+		}
+
+		return e(ctx, req)
+	}
+}
+
+// TypedEndpoint converts a go-kit endpoint into a typed endpoint.
+//
+// The returned endpoint returns a *TypeError if the response is not of type Resp.
+func TypedEndpoint[Req, Resp any](e endpoint.Endpoint) Typed[Req, Resp] {
+	return func(ctx context.Context, request Req) (Resp, error) {
+		var zero Resp
+
+		response, err := e(ctx, request)
+		if err != nil {
+			return zero, err
+		}
+
+		resp, ok := response.(Resp)
+		if !ok {
+			if response != nil || !isNillable[Resp]() {
+				return zero, newTypeError[Resp]("response", response)
+			}
+		}
+
+		return resp, nil
+	}
+}
+
+// TypedMiddleware is a type-safe version of endpoint.Middleware.
+type TypedMiddleware[Req, Resp any] func(Typed[Req, Resp]) Typed[Req, Resp]
+
+// Middleware converts a typed middleware into a go-kit middleware.
+func (m TypedMiddleware[Req, Resp]) Middleware() endpoint.Middleware {
+	return func(next endpoint.Endpoint) endpoint.Endpoint {
+		return m(TypedEndpoint[Req, Resp](next)).Endpoint()
+	}
+}
+
+// CombineTyped composes a single typed middleware from a list.
+func CombineTyped[Req, Resp any](mw ...TypedMiddleware[Req, Resp]) TypedMiddleware[Req, Resp] {
+	return func(e Typed[Req, Resp]) Typed[Req, Resp] {
+		for i := len(mw) - 1; i >= 0; i-- { // traverse middleware in a reverse order
+			e = mw[i](e)
+		}
+
+		return e
+	}
+}
+
+// NewTypedEndpoint returns a typed endpoint wrapped with the middleware preconfigured in a Factory.
+//
+// Middleware may change the response type (eg. FailerMiddleware),
+// so the returned endpoint is a go-kit endpoint that can be passed to transports.
+func NewTypedEndpoint[Req, Resp any](factory Factory, name string, e Typed[Req, Resp]) endpoint.Endpoint {
+	return factory.NewEndpoint(name, e.Endpoint())
+}
+
+// TypeError is returned when a request or a response passed through an untyped endpoint
+// does not match the type expected by a typed endpoint.
+type TypeError struct {
+	// Subject is either "request" or "response".
+	Subject string
+
+	Expected reflect.Type
+	Actual   reflect.Type
+}
+
+func newTypeError[T any](subject string, value interface{}) *TypeError {
+	return &TypeError{
+		Subject:  subject,
+		Expected: reflect.TypeOf((*T)(nil)).Elem(),
+		Actual:   reflect.TypeOf(value),
+	}
+}
+
+// Error implements the error interface.
+func (e *TypeError) Error() string {
+	actual := "nil"
+	if e.Actual != nil {
+		actual = e.Actual.String()
+	}
+
+	return fmt.Sprintf("unexpected %s type: expected %s, got %s", e.Subject, e.Expected, actual)
+}
+
+// isNillable checks if nil is a valid value of T.
+func isNillable[T any]() bool {
+	switch reflect.TypeOf((*T)(nil)).Elem().Kind() {
+	case reflect.Chan, reflect.Func, reflect.Interface, reflect.Map, reflect.Pointer, reflect.Slice:
+		return true
+
+	default:
+		return false
+	}
+}
@@ -0,0 +1,173 @@
+package endpoint
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/go-kit/kit/endpoint"
+)
+
+type greetRequest struct {
+	Name string
+}
+
+type greetResponse struct {
+	Greeting string
+}
+
+func greet(_ context.Context, req greetRequest) (greetResponse, error) {
+	return greetResponse{Greeting: "Hello, " + req.Name}, nil
+}
+
+func ExampleTyped() {
+	var e Typed[greetRequest, greetResponse] = greet
+
+	resp, err := e.Endpoint()(context.Background(), greetRequest{Name: "John"})
+	if err != nil {
+		panic(err)
+	}
+
+	fmt.Println(resp.(greetResponse).Greeting)
+
+	_, err = e.Endpoint()(context.Background(), "John")
+
+	fmt.Println(err)
+
+	// Output:
+	// Hello, John
+	// unexpected request type: expected endpoint.greetRequest, got string
+}
+
+func TestTyped_Endpoint(t *testing.T) {
+	t.Run("nil_pointer_request", func(t *testing.T) {
+		var e Typed[*greetRequest, string] = func(_ context.Context, req *greetRequest) (string, error) {
+			if req != nil {
+				t.Error("request is supposed to be nil")
+			}
+
+			return "ok", nil
+		}
+
+		_, err := e.Endpoint()(context.Background(), nil)
+		if err != nil {
+			t.Fatal("unexpected error: ", err)
+		}
+	})
+
+	t.Run("nil_struct_request", func(t *testing.T) {
+		var e Typed[greetRequest, greetResponse] = greet
+
+		_, err := e.Endpoint()(context.Background(), nil)
+
+		var typeErr *TypeError
+		if !errors.As(err, &typeErr) {
+			t.Fatalf("error is supposed to be a type error, got: %v", err)
+		}
+
+		if want, have := "request", typeErr.Subject; want != have {
+			t.Errorf("unexpected subject\nexpected: %s\nactual:   %s", want, have)
+		}
+	})
+}
+
+func TestTypedEndpoint(t *testing.T) {
+	t.Run("ok", func(t *testing.T) {
+		e := TypedEndpoint[greetRequest, greetResponse](Typed[greetRequest, greetResponse](greet).Endpoint())
+
+		resp, err := e(context.Background(), greetRequest{Name: "John"})
+		if err != nil {
+			t.Fatal("unexpected error: ", err)
+		}
+
+		if want, have := "Hello, John", resp.Greeting; want != have {
+			t.Errorf("unexpected greeting\nexpected: %s\nactual:   %s", want, have)
+		}
+	})
+
+	t.Run("error", func(t *testing.T) {
+		berr := errors.New("error")
+
+		e := TypedEndpoint[greetRequest, greetResponse](func(context.Context, interface{}) (interface{}, error) {
+			return nil, berr
+		})
+
+		_, err := e(context.Background(), greetRequest{})
+		if !errors.Is(err, berr) {
+			t.Errorf("unexpected error\nexpected: %v\nactual:   %v", berr, err)
+		}
+	})
+
+	t.Run("wrong_response_type", func(t *testing.T) {
+		e := TypedEndpoint[greetRequest, greetResponse](func(context.Context, interface{}) (interface{}, error) {
+			return "response", nil
+		})
+
+		_, err := e(context.Background(), greetRequest{})
+
+		var typeErr *TypeError
+		if !errors.As(err, &typeErr) {
+			t.Fatalf("error is supposed to be a type error, got: %v", err)
+		}
+
+		if want, have := "response", typeErr.Subject; want != have {
+			t.Errorf("unexpected subject\nexpected: %s\nactual:   %s", want, have)
+		}
+	})
+}
+
+func TestTypedMiddleware(t *testing.T) {
+	annotate := func(suffix string) TypedMiddleware[greetRequest, greetResponse] {
+		return func(next Typed[greetRequest, greetResponse]) Typed[greetRequest, greetResponse] {
+			return func(ctx context.Context, req greetRequest) (greetResponse, error) {
+				resp, err := next(ctx, req)
+				resp.Greeting += suffix
+
+				return resp, err
+			}
+		}
+	}
+
+	mw := CombineTyped(annotate("!"), annotate("?"))
+
+	var e endpoint.Endpoint = Typed[greetRequest, greetResponse](greet).Endpoint()
+
+	e = mw.Middleware()(e)
+
+	resp, err := e(context.Background(), greetRequest{Name: "John"})
+	if err != nil {
+		t.Fatal("unexpected error: ", err)
+	}
+
+	if want, have := "Hello, John?!", resp.(greetResponse).Greeting; want != have {
+		t.Errorf("unexpected greeting\nexpected: %s\nactual:   %s", want, have)
+	}
+}
+
+func TestNewTypedEndpoint(t *testing.T) {
+	var name string
+
+	factory := NewFactory(func(n string) endpoint.Middleware {
+		name = n
+
+		return FailerMiddleware(func(err error) bool { return true })
+	})
+
+	e := NewTypedEndpoint(factory, "greet", func(context.Context, greetRequest) (greetResponse, error) {
+		return greetResponse{}, errors.New("error")
+	})
+
+	resp, err := e(context.Background(), greetRequest{})
+	if err != nil {
+		t.Fatal("error is supposed to be wrapped by the response")
+	}
+
+	if _, ok := resp.(endpoint.Failer); !ok {
+		t.Error("response is supposed to be a failure response")
+	}
+
+	if want, have := "greet", name; want != have {
+		t.Errorf("unexpected operation name\nexpected: %s\nactual:   %s", want, have)
+	}
+}
@@ -0,0 +1,116 @@
+// Package log provides logging tools that integrate with other kitx packages.
+package log
+
+import (
+	"context"
+	"log/slog"
+
+	"github.com/sagikazarmark/kitx/correlation"
+	"github.com/sagikazarmark/kitx/endpoint"
+)
+
+// Attribute keys added by Handler.
+const (
+	CorrelationIDKey = "correlation_id"
+	OperationKey     = "operation"
+)
+
+// Handler is a slog.Handler that adds the correlation ID and the endpoint operation name (if any)
+// from the context to every record.
+//
+// The attributes are always added at the top level, even if the record is logged within a group (see slog.Logger.WithGroup).
+//
+// Records need to be logged with a context (eg. using slog.InfoContext) for the attributes to be added.
+type Handler struct {
+	handler slog.Handler
+
+	// root is the wrapped handler before the first group is opened.
+	root slog.Handler
+
+	// groupOps replay the groups and attributes added after the first group on top of root.
+	groupOps []func(slog.Handler) slog.Handler
+}
+
+// NewHandler wraps a slog.Handler and adds the correlation ID and the endpoint operation name to records.
+func NewHandler(handler slog.Handler) *Handler {
+	return &Handler{
+		handler: handler,
+		root:    handler,
+	}
+}
+
+// Enabled implements slog.Handler.
+func (h *Handler) Enabled(ctx context.Context, level slog.Level) bool {
+	return h.handler.Enabled(ctx, level)
+}
+
+// Handle implements slog.Handler.
+func (h *Handler) Handle(ctx context.Context, record slog.Record) error {
+	var attrs []slog.Attr
+
+	if cid, ok := correlation.FromContext(ctx); ok && cid != "" {
+		attrs = append(attrs, slog.String(CorrelationIDKey, cid))
+	}
+
+	if name, ok := endpoint.OperationName(ctx); ok && name != "" {
+		attrs = append(attrs, slog.String(OperationKey, name))
+	}
+
+	if len(attrs) == 0 {
+		return h.handler.Handle(ctx, record)
+	}
+
+	// Attributes added to the record would end up in the current group:
+	// add them to the handler before the first group is opened instead
+	if len(h.groupOps) > 0 {
+		handler := h.root.WithAttrs(attrs)
+
+		for _, op := range h.groupOps {
+			handler = op(handler)
+		}
+
+		return handler.Handle(ctx, record)
+	}
+
+	record = record.Clone()
+	record.AddAttrs(attrs...)
+
+	return h.handler.Handle(ctx, record)
+}
+
+// WithAttrs implements slog.Handler.
+func (h *Handler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	if len(attrs) == 0 {
+		return h
+	}
+
+	if len(h.groupOps) == 0 {
+		return NewHandler(h.handler.WithAttrs(attrs))
+	}
+
+	return h.with(h.handler.WithAttrs(attrs), func(handler slog.Handler) slog.Handler {
+		return handler.WithAttrs(attrs)
+	})
+}
+
+// WithGroup implements slog.Handler.
+func (h *Handler) WithGroup(name string) slog.Handler {
+	if name == "" {
+		return h
+	}
+
+	return h.with(h.handler.WithGroup(name), func(handler slog.Handler) slog.Handler {
+		return handler.WithGroup(name)
+	})
+}
+
+func (h *Handler) with(handler slog.Handler, op func(slog.Handler) slog.Handler) *Handler {
+	groupOps := make([]func(slog.Handler) slog.Handler, 0, len(h.groupOps)+1)
+	groupOps = append(groupOps, h.groupOps...)
+
+	return &Handler{
+		handler:  handler,
+		root:     h.root,
+		groupOps: append(groupOps, op),
+	}
+}
@@ -0,0 +1,72 @@
+package log
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"testing"
+
+	"github.com/go-kit/kit/endpoint"
+
+	"github.com/sagikazarmark/kitx/correlation"
+	kitxendpoint "github.com/sagikazarmark/kitx/endpoint"
+)
+
+func newTestLogger(buf *bytes.Buffer) *slog.Logger {
+	return slog.New(NewHandler(slog.NewTextHandler(buf, &slog.HandlerOptions{
+		ReplaceAttr: func(_ []string, a slog.Attr) slog.Attr {
+			if a.Key == slog.TimeKey {
+				return slog.Attr{}
+			}
+
+			return a
+		},
+	})))
+}
+
+func TestHandler(t *testing.T) {
+	var buf bytes.Buffer
+
+	logger := newTestLogger(&buf)
+
+	var e endpoint.Endpoint = func(ctx context.Context, _ interface{}) (interface{}, error) {
+		logger.InfoContext(ctx, "hello", "key", "value")
+
+		return nil, nil
+	}
+
+	e = kitxendpoint.OperationNameMiddleware("greeter.Hello")(e)
+
+	_, _ = e(correlation.ToContext(context.Background(), "1234"), nil)
+
+	expected := "level=INFO msg=hello key=value correlation_id=1234 operation=greeter.Hello\n"
+	if want, have := expected, buf.String(); want != have {
+		t.Errorf("unexpected log output\nexpected: %s\nactual:   %s", want, have)
+	}
+}
+
+func TestHandler_NoContext(t *testing.T) {
+	var buf bytes.Buffer
+
+	logger := newTestLogger(&buf)
+
+	logger.With("key", "value").WithGroup("group").Info("hello", "foo", "bar")
+
+	expected := "level=INFO msg=hello key=value group.foo=bar\n"
+	if want, have := expected, buf.String(); want != have {
+		t.Errorf("unexpected log output\nexpected: %s\nactual:   %s", want, have)
+	}
+}
+
+func TestHandler_Group(t *testing.T) {
+	var buf bytes.Buffer
+
+	logger := newTestLogger(&buf).With("key", "value").WithGroup("req").With("method", "GET")
+
+	logger.InfoContext(correlation.ToContext(context.Background(), "1234"), "hello", "path", "/")
+
+	expected := "level=INFO msg=hello key=value correlation_id=1234 req.method=GET req.path=/\n"
+	if want, have := expected, buf.String(); want != have {
+		t.Errorf("unexpected log output\nexpected: %s\nactual:   %s", want, have)
+	}
+}
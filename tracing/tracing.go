@@ -0,0 +1,119 @@
+// Package tracing adds OpenTelemetry tracing to endpoints and transports.
+package tracing
+
+import (
+	"context"
+
+	"github.com/go-kit/kit/endpoint"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/sagikazarmark/kitx/correlation"
+	kitxendpoint "github.com/sagikazarmark/kitx/endpoint"
+)
+
+// instrumentationName is the name of the tracer used by this package.
+const instrumentationName = "github.com/sagikazarmark/kitx/tracing"
+
+// Span attribute keys.
+const (
+	CorrelationIDKey = attribute.Key("correlation.id")
+	FailedKey        = attribute.Key("endpoint.failed")
+)
+
+// Option configures tracing.
+type Option interface {
+	apply(o *options)
+}
+
+type options struct {
+	tracerProvider trace.TracerProvider
+	propagator     propagation.TextMapPropagator
+	spanKind       trace.SpanKind
+}
+
+type optionFunc func(o *options)
+
+func (fn optionFunc) apply(o *options) {
+	fn(o)
+}
+
+// WithTracerProvider sets the tracer provider used to start spans.
+// By default, the global tracer provider is used.
+func WithTracerProvider(tp trace.TracerProvider) Option {
+	return optionFunc(func(o *options) { o.tracerProvider = tp })
+}
+
+// WithPropagator sets the propagator used to extract and inject trace context.
+// By default, the global propagator is used.
+func WithPropagator(p propagation.TextMapPropagator) Option {
+	return optionFunc(func(o *options) { o.propagator = p })
+}
+
+// WithSpanKind sets the kind of the spans started by Middleware.
+// By default, spans are internal.
+func WithSpanKind(kind trace.SpanKind) Option {
+	return optionFunc(func(o *options) { o.spanKind = kind })
+}
+
+func newOptions(opts []Option) options {
+	o := options{
+		spanKind: trace.SpanKindInternal,
+	}
+
+	for _, opt := range opts {
+		opt.apply(&o)
+	}
+
+	if o.tracerProvider == nil {
+		o.tracerProvider = otel.GetTracerProvider()
+	}
+
+	if o.propagator == nil {
+		o.propagator = otel.GetTextMapPropagator()
+	}
+
+	return o
+}
+
+// Middleware returns a MiddlewareFactory that starts a span named after the operation for every endpoint call.
+//
+// Errors are recorded on the span and set its status to error.
+// Failed responses (see endpoint.Failer) are recorded on the span and marked with the endpoint.failed attribute,
+// but they do not change the span status, since they are usually business errors.
+// The correlation ID (if any) is added to the span as an attribute.
+func Middleware(opts ...Option) kitxendpoint.MiddlewareFactory {
+	o := newOptions(opts)
+	tracer := o.tracerProvider.Tracer(instrumentationName)
+
+	return kitxendpoint.NameMiddlewareFactory("tracing", func(name string) endpoint.Middleware {
+		return func(next endpoint.Endpoint) endpoint.Endpoint {
+			return func(ctx context.Context, request interface{}) (interface{}, error) {
+				ctx, span := tracer.Start(ctx, name, trace.WithSpanKind(o.spanKind))
+				defer span.End()
+
+				if cid, ok := correlation.FromContext(ctx); ok && cid != "" {
+					span.SetAttributes(CorrelationIDKey.String(cid))
+				}
+
+				response, err := next(ctx, request)
+				if err != nil {
+					span.RecordError(err)
+					span.SetStatus(codes.Error, err.Error())
+
+					return response, err
+				}
+
+				if f, ok := response.(endpoint.Failer); ok && f.Failed() != nil {
+					span.RecordError(f.Failed())
+					span.SetAttributes(FailedKey.Bool(true))
+				}
+
+				return response, nil
+			}
+		}
+	})
+}
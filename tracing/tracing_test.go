@@ -0,0 +1,113 @@
+package tracing
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+
+	"github.com/sagikazarmark/kitx/correlation"
+)
+
+type failer struct {
+	err error
+}
+
+func (f failer) Failed() error {
+	return f.err
+}
+
+func newTracerProvider() (*sdktrace.TracerProvider, *tracetest.InMemoryExporter) {
+	exporter := tracetest.NewInMemoryExporter()
+
+	return sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter)), exporter
+}
+
+func TestMiddleware(t *testing.T) {
+	tests := []struct {
+		name     string
+		response interface{}
+		err      error
+		status   codes.Code
+		failed   bool
+		events   int
+	}{
+		{
+			name:     "success",
+			response: "response",
+			status:   codes.Unset,
+		},
+		{
+			name:     "failure",
+			response: failer{errors.New("not found")},
+			status:   codes.Unset,
+			failed:   true,
+			events:   1,
+		},
+		{
+			name:   "error",
+			err:    errors.New("connection refused"),
+			status: codes.Error,
+			events: 1,
+		},
+	}
+
+	for _, test := range tests {
+		test := test
+
+		t.Run(test.name, func(t *testing.T) {
+			tp, exporter := newTracerProvider()
+
+			e := Middleware(WithTracerProvider(tp))("greet")(func(context.Context, interface{}) (interface{}, error) {
+				return test.response, test.err
+			})
+
+			_, _ = e(correlation.ToContext(context.Background(), "1234"), nil)
+
+			spans := exporter.GetSpans()
+			if want, have := 1, len(spans); want != have {
+				t.Fatalf("unexpected number of spans\nexpected: %d\nactual:   %d", want, have)
+			}
+
+			span := spans[0]
+
+			if want, have := "greet", span.Name; want != have {
+				t.Errorf("unexpected span name\nexpected: %s\nactual:   %s", want, have)
+			}
+
+			if want, have := test.status, span.Status.Code; want != have {
+				t.Errorf("unexpected span status\nexpected: %v\nactual:   %v", want, have)
+			}
+
+			if want, have := test.events, len(span.Events); want != have {
+				t.Errorf("unexpected number of span events\nexpected: %d\nactual:   %d", want, have)
+			}
+
+			var (
+				cid    string
+				failed bool
+			)
+
+			for _, attr := range span.Attributes {
+				switch attr.Key {
+				case CorrelationIDKey:
+					cid = attr.Value.AsString()
+
+				case FailedKey:
+					failed = attr.Value.AsBool()
+				}
+			}
+
+			if want, have := "1234", cid; want != have {
+				t.Errorf("unexpected correlation ID attribute\nexpected: %s\nactual:   %s", want, have)
+			}
+
+			if want, have := test.failed, failed; want != have {
+				t.Errorf("unexpected failed attribute\nexpected: %v\nactual:   %v", want, have)
+			}
+		})
+	}
+}
@@ -0,0 +1,119 @@
+package tracing
+
+import (
+	"context"
+	"net/http"
+
+	kitgrpc "github.com/go-kit/kit/transport/grpc"
+	kithttp "github.com/go-kit/kit/transport/http"
+	"go.opentelemetry.io/otel/propagation"
+	"google.golang.org/grpc/metadata"
+
+	"github.com/sagikazarmark/kitx/transport/graphql"
+)
+
+// HTTPServerTrace returns a server option that extracts trace context from incoming HTTP requests.
+func HTTPServerTrace(opts ...Option) kithttp.ServerOption {
+	o := newOptions(opts)
+
+	return kithttp.ServerBefore(func(ctx context.Context, r *http.Request) context.Context {
+		return o.propagator.Extract(ctx, propagation.HeaderCarrier(r.Header))
+	})
+}
+
+// HTTPClientTrace returns a client option that injects trace context into outgoing HTTP requests.
+func HTTPClientTrace(opts ...Option) kithttp.ClientOption {
+	o := newOptions(opts)
+
+	return kithttp.ClientBefore(func(ctx context.Context, r *http.Request) context.Context {
+		o.propagator.Inject(ctx, propagation.HeaderCarrier(r.Header))
+
+		return ctx
+	})
+}
+
+// GRPCServerTrace returns a server option that extracts trace context from incoming gRPC metadata.
+func GRPCServerTrace(opts ...Option) kitgrpc.ServerOption {
+	o := newOptions(opts)
+
+	return kitgrpc.ServerBefore(func(ctx context.Context, md metadata.MD) context.Context {
+		return o.propagator.Extract(ctx, metadataCarrier(md))
+	})
+}
+
+// GRPCClientTrace returns a client option that injects trace context into outgoing gRPC metadata.
+func GRPCClientTrace(opts ...Option) kitgrpc.ClientOption {
+	o := newOptions(opts)
+
+	return kitgrpc.ClientBefore(grpcInject(o))
+}
+
+func grpcInject(o options) kitgrpc.ClientRequestFunc {
+	return func(ctx context.Context, md *metadata.MD) context.Context {
+		if *md == nil {
+			*md = metadata.MD{}
+		}
+
+		o.propagator.Inject(ctx, metadataCarrier(*md))
+
+		return ctx
+	}
+}
+
+// GraphQLServerTrace returns a server option that extracts trace context from incoming GraphQL requests
+// implementing propagation.TextMapCarrier.
+//
+// GraphQL requests are usually served over HTTP, so the trace context should be extracted from the request header
+// by the HTTP layer (eg. using HTTPMiddleware).
+func GraphQLServerTrace(opts ...Option) graphql.ServerOption {
+	o := newOptions(opts)
+
+	return graphql.ServerBefore(func(ctx context.Context, request interface{}) context.Context {
+		carrier, ok := request.(propagation.TextMapCarrier)
+		if !ok {
+			return ctx
+		}
+
+		return o.propagator.Extract(ctx, carrier)
+	})
+}
+
+// HTTPMiddleware is a net/http middleware that extracts trace context from incoming requests.
+//
+// It can be used with handlers that are not served by a go-kit HTTP server (eg. GraphQL handlers).
+func HTTPMiddleware(opts ...Option) func(next http.Handler) http.Handler {
+	o := newOptions(opts)
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			ctx := o.propagator.Extract(r.Context(), propagation.HeaderCarrier(r.Header))
+
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	}
+}
+
+// metadataCarrier adapts gRPC metadata to propagation.TextMapCarrier.
+type metadataCarrier metadata.MD
+
+func (c metadataCarrier) Get(key string) string {
+	values := metadata.MD(c).Get(key)
+	if len(values) == 0 {
+		return ""
+	}
+
+	return values[0]
+}
+
+func (c metadataCarrier) Set(key string, value string) {
+	metadata.MD(c).Set(key, value)
+}
+
+func (c metadataCarrier) Keys() []string {
+	keys := make([]string, 0, len(c))
+	for key := range c {
+		keys = append(keys, key)
+	}
+
+	return keys
+}
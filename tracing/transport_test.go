@@ -0,0 +1,151 @@
+package tracing
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	kitgrpc "github.com/go-kit/kit/transport/grpc"
+	kithttp "github.com/go-kit/kit/transport/http"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+	"google.golang.org/grpc/metadata"
+
+	"github.com/sagikazarmark/kitx/transport/graphql"
+)
+
+var propagator = propagation.TraceContext{}
+
+// spanContext returns a context with a span started by a test tracer provider.
+func spanContext(t *testing.T) (context.Context, trace.SpanContext) {
+	t.Helper()
+
+	tp, _ := newTracerProvider()
+
+	ctx, span := tp.Tracer("test").Start(context.Background(), "client")
+	t.Cleanup(func() { span.End() })
+
+	return ctx, span.SpanContext()
+}
+
+func assertRemoteSpanContext(t *testing.T, ctx context.Context, expected trace.SpanContext) {
+	t.Helper()
+
+	sc := trace.SpanContextFromContext(ctx)
+
+	if !sc.IsRemote() {
+		t.Error("span context is expected to be remote")
+	}
+
+	if want, have := expected.TraceID(), sc.TraceID(); want != have {
+		t.Errorf("unexpected trace ID\nexpected: %s\nactual:   %s", want, have)
+	}
+
+	if want, have := expected.SpanID(), sc.SpanID(); want != have {
+		t.Errorf("unexpected span ID\nexpected: %s\nactual:   %s", want, have)
+	}
+}
+
+func nopEndpoint(ctx context.Context, _ interface{}) (interface{}, error) {
+	return ctx, nil
+}
+
+func TestHTTPTrace(t *testing.T) {
+	var serverCtx context.Context
+
+	server := httptest.NewServer(kithttp.NewServer(
+		func(ctx context.Context, _ interface{}) (interface{}, error) {
+			serverCtx = ctx
+
+			return nil, nil
+		},
+		kithttp.NopRequestDecoder,
+		kithttp.EncodeJSONResponse,
+		HTTPServerTrace(WithPropagator(propagator)),
+	))
+	defer server.Close()
+
+	u, _ := url.Parse(server.URL)
+
+	client := kithttp.NewClient(
+		http.MethodGet,
+		u,
+		func(context.Context, *http.Request, interface{}) error { return nil },
+		func(context.Context, *http.Response) (interface{}, error) { return nil, nil },
+		HTTPClientTrace(WithPropagator(propagator)),
+	)
+
+	ctx, sc := spanContext(t)
+
+	if _, err := client.Endpoint()(ctx, nil); err != nil {
+		t.Fatal(err)
+	}
+
+	assertRemoteSpanContext(t, serverCtx, sc)
+}
+
+func TestGRPCTrace(t *testing.T) {
+	ctx, sc := spanContext(t)
+
+	var md metadata.MD
+
+	grpcInject(newOptions([]Option{WithPropagator(propagator)}))(ctx, &md)
+
+	server := kitgrpc.NewServer(
+		nopEndpoint,
+		func(context.Context, interface{}) (interface{}, error) { return nil, nil },
+		func(_ context.Context, response interface{}) (interface{}, error) { return response, nil },
+		GRPCServerTrace(WithPropagator(propagator)),
+	)
+
+	_, response, err := server.ServeGRPC(metadata.NewIncomingContext(context.Background(), md), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assertRemoteSpanContext(t, response.(context.Context), sc)
+}
+
+type carrierRequest struct {
+	propagation.MapCarrier
+}
+
+func TestGraphQLServerTrace(t *testing.T) {
+	ctx, sc := spanContext(t)
+
+	request := carrierRequest{propagation.MapCarrier{}}
+	propagator.Inject(ctx, request)
+
+	server := graphql.NewServer(
+		nopEndpoint,
+		func(context.Context, interface{}) (interface{}, error) { return nil, nil },
+		func(_ context.Context, response interface{}) (interface{}, error) { return response, nil },
+		GraphQLServerTrace(WithPropagator(propagator)),
+	)
+
+	_, response, err := server.ServeGraphQL(context.Background(), request)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assertRemoteSpanContext(t, response.(context.Context), sc)
+}
+
+func TestHTTPMiddleware(t *testing.T) {
+	ctx, sc := spanContext(t)
+
+	r := httptest.NewRequest(http.MethodGet, "/", nil)
+	propagator.Inject(ctx, propagation.HeaderCarrier(r.Header))
+
+	var handlerCtx context.Context
+
+	handler := HTTPMiddleware(WithPropagator(propagator))(http.HandlerFunc(func(_ http.ResponseWriter, r *http.Request) {
+		handlerCtx = r.Context()
+	}))
+
+	handler.ServeHTTP(httptest.NewRecorder(), r)
+
+	assertRemoteSpanContext(t, handlerCtx, sc)
+}
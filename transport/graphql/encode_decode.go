@@ -23,11 +23,24 @@ type EncodeResponseFunc func(context.Context, interface{}) (response interface{}
 type EncodeErrorResponseFunc func(context.Context, error) error
 
 // ErrorResponseEncoder encodes the passed response object to a GraphQL response or error.
+//
+// Failed responses are encoded using the error encoder.
+// If a failed response carries partial data (see PartialDataer, implemented by kitx's endpoint.Failure),
+// the data is encoded and returned alongside the error.
 func ErrorResponseEncoder(
 	encoder EncodeResponseFunc,
 	errorEncoder EncodeErrorResponseFunc,
 ) EncodeResponseFunc {
 	return func(ctx context.Context, resp interface{}) (interface{}, error) {
+		if f, ok := resp.(partialFailer); ok && f.Failed() != nil && f.PartialData() != nil {
+			data, err := encoder(ctx, f.PartialData())
+			if err != nil {
+				return nil, err
+			}
+
+			return data, errorEncoder(ctx, f.Failed())
+		}
+
 		if f, ok := resp.(endpoint.Failer); ok && f.Failed() != nil {
 			return nil, errorEncoder(ctx, f.Failed())
 		}
@@ -35,3 +48,14 @@ func ErrorResponseEncoder(
 		return encoder(ctx, resp)
 	}
 }
+
+// PartialDataer is checked by ErrorResponseEncoder. If a failed response implements PartialDataer,
+// the partial data is returned alongside the error.
+type PartialDataer interface {
+	PartialData() interface{}
+}
+
+type partialFailer interface {
+	endpoint.Failer
+	PartialDataer
+}
@@ -20,6 +20,7 @@ type Server struct {
 	e            endpoint.Endpoint
 	dec          DecodeRequestFunc
 	enc          EncodeResponseFunc
+	before       []ServerRequestFunc
 	errorHandler transport.ErrorHandler
 }
 
@@ -58,6 +59,15 @@ func (fn serverOptionFunc) apply(s *Server) {
 	fn(s)
 }
 
+// ServerRequestFunc may take information from a GraphQL request and put it into a request context.
+// ServerRequestFuncs are executed prior to invoking the endpoint (and decoding the request).
+type ServerRequestFunc func(ctx context.Context, request interface{}) context.Context
+
+// ServerBefore functions are executed on the GraphQL request object before the request is decoded.
+func ServerBefore(before ...ServerRequestFunc) ServerOption {
+	return serverOptionFunc(func(s *Server) { s.before = append(s.before, before...) })
+}
+
 // ServerErrorHandler is used to handle non-terminal errors. By default, non-terminal errors
 // are ignored.
 func ServerErrorHandler(errorHandler transport.ErrorHandler) ServerOption {
@@ -73,6 +83,10 @@ func (s Server) ServeGraphQL(ctx context.Context, req interface{}) (context.Cont
 		graphqlResp interface{}
 	)
 
+	for _, f := range s.before {
+		ctx = f(ctx, req)
+	}
+
 	request, err = s.dec(ctx, req)
 	if err != nil {
 		s.errorHandler.Handle(ctx, err)
@@ -85,10 +99,11 @@ func (s Server) ServeGraphQL(ctx context.Context, req interface{}) (context.Cont
 		return ctx, nil, err
 	}
 
+	// The encoder MAY return partial data alongside an error
 	graphqlResp, err = s.enc(ctx, response)
 	if err != nil {
 		s.errorHandler.Handle(ctx, err)
-		return ctx, nil, err
+		return ctx, graphqlResp, err
 	}
 
 	return ctx, graphqlResp, nil
@@ -5,8 +5,15 @@ import (
 
 	"github.com/go-kit/kit/endpoint"
 	kitgrpc "github.com/go-kit/kit/transport/grpc"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
 	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/anypb"
+
+	"github.com/sagikazarmark/kitx/correlation"
+	kitxendpoint "github.com/sagikazarmark/kitx/endpoint"
 )
 
 // EncodeErrorResponseFunc transforms the passed error to a gRPC code error.
@@ -14,11 +21,39 @@ import (
 type EncodeErrorResponseFunc func(context.Context, error) error
 
 // ErrorResponseEncoder encodes the passed response object to a gRPC response or error.
+//
+// Failed responses are encoded using the error encoder.
+// If the response is a *endpoint.Failure, its gRPC code (if any) overrides the code returned by the error encoder
+// and its headers are sent as header metadata.
+// Partial response data (endpoint.Failure.Data) is not encoded: gRPC responses carry either a message or an error.
 func ErrorResponseEncoder(
 	encoder kitgrpc.EncodeResponseFunc,
 	errorEncoder EncodeErrorResponseFunc,
 ) kitgrpc.EncodeResponseFunc {
 	return func(ctx context.Context, resp interface{}) (interface{}, error) {
+		if failure, ok := kitxendpoint.AsFailure(resp); ok && failure.Failed() != nil {
+			if len(failure.Header) > 0 {
+				md := metadata.MD{}
+				for key, values := range failure.Header {
+					md.Append(key, values...)
+				}
+
+				// Setting headers fails outside of a gRPC server handler: there is nothing to do about it
+				_ = grpc.SetHeader(ctx, md)
+			}
+
+			err := errorEncoder(ctx, failure.Failed())
+
+			if failure.GRPCCode != codes.OK {
+				st := status.Convert(err).Proto()
+				st.Code = int32(failure.GRPCCode)
+
+				err = status.FromProto(st).Err()
+			}
+
+			return nil, err
+		}
+
 		if f, ok := resp.(endpoint.Failer); ok && f.Failed() != nil {
 			return nil, errorEncoder(ctx, f.Failed())
 		}
@@ -35,11 +70,18 @@ type StatusConverter interface {
 
 type defaultErrorStatusConverter struct{}
 
-func (d defaultErrorStatusConverter) NewStatus(_ context.Context, _ error) *status.Status {
+func (d defaultErrorStatusConverter) NewStatus(_ context.Context, err error) *status.Status {
+	if st, ok := StatusFromError(err); ok {
+		return st
+	}
+
 	return status.New(codes.Internal, "something went wrong")
 }
 
 // NewStatusErrorResponseEncoder returns an error response encoder that encodes errors as gRPC Status errors.
+//
+// If the context contains a correlation ID, it is added to the status as ErrorInfo metadata
+// (see CorrelationIDMetadataKey).
 func NewStatusErrorResponseEncoder(statusConverter StatusConverter) EncodeErrorResponseFunc {
 	return func(ctx context.Context, err error) error {
 		// Do not convert gRPC errors
@@ -47,13 +89,63 @@ func NewStatusErrorResponseEncoder(statusConverter StatusConverter) EncodeErrorR
 			return err
 		}
 
-		return statusConverter.NewStatus(ctx, err).Err()
+		return withCorrelationID(ctx, statusConverter.NewStatus(ctx, err)).Err()
+	}
+}
+
+// CorrelationIDMetadataKey is the errdetails.ErrorInfo metadata key containing the correlation ID in error statuses.
+const CorrelationIDMetadataKey = "correlation_id"
+
+// withCorrelationID adds the correlation ID to the ErrorInfo detail of a status
+// (or adds an ErrorInfo detail if there is none).
+func withCorrelationID(ctx context.Context, st *status.Status) *status.Status {
+	cid, ok := correlation.FromContext(ctx)
+	if !ok || cid == "" {
+		return st
 	}
+
+	p := st.Proto()
+
+	for i, detail := range p.GetDetails() {
+		var errorInfo errdetails.ErrorInfo
+
+		if !detail.MessageIs(&errorInfo) || detail.UnmarshalTo(&errorInfo) != nil {
+			continue
+		}
+
+		// Do not override an existing value
+		if _, ok := errorInfo.GetMetadata()[CorrelationIDMetadataKey]; ok {
+			return st
+		}
+
+		if errorInfo.Metadata == nil {
+			errorInfo.Metadata = map[string]string{}
+		}
+
+		errorInfo.Metadata[CorrelationIDMetadataKey] = cid
+
+		a, err := anypb.New(&errorInfo)
+		if err != nil {
+			return st
+		}
+
+		p.Details[i] = a
+
+		return status.FromProto(p)
+	}
+
+	s, err := st.WithDetails(&errdetails.ErrorInfo{Metadata: map[string]string{CorrelationIDMetadataKey: cid}})
+	if err != nil {
+		return st
+	}
+
+	return s
 }
 
 // NewDefaultStatusErrorResponseEncoder returns an error response encoder that encodes errors as gRPC Status errors.
 //
-// The returned encoder encodes every error as Internal error.
+// The returned encoder encodes every error as Internal error,
+// except errors returned by kitx endpoint middleware (see StatusFromError).
 func NewDefaultStatusErrorResponseEncoder() EncodeErrorResponseFunc {
 	return NewStatusErrorResponseEncoder(defaultErrorStatusConverter{})
 }
@@ -5,8 +5,12 @@ import (
 	"errors"
 	"testing"
 
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
+
+	"github.com/sagikazarmark/kitx/correlation"
+	kitxendpoint "github.com/sagikazarmark/kitx/endpoint"
 )
 
 type statusConverterStub struct {
@@ -34,3 +38,77 @@ func TestStatusErrorResponseEncoder(t *testing.T) {
 		t.Errorf("unexpected message\nexpected: %s\nactual:   %s", want, have)
 	}
 }
+
+func TestStatusErrorResponseEncoder_CorrelationID(t *testing.T) {
+	errorEncoder := NewDefaultStatusErrorResponseEncoder()
+
+	err := errorEncoder(correlation.ToContext(context.Background(), "1234"), errors.New("error"))
+
+	s := status.Convert(err)
+
+	if want, have := codes.Internal, s.Code(); want != have {
+		t.Errorf("unexpected code\nexpected: %d\nactual:   %d", want, have)
+	}
+
+	details := s.Details()
+	if len(details) != 1 {
+		t.Fatalf("unexpected number of details\nexpected: %d\nactual:   %d", 1, len(details))
+	}
+
+	errorInfo, ok := details[0].(*errdetails.ErrorInfo)
+	if !ok {
+		t.Fatalf("unexpected detail type: %T", details[0])
+	}
+
+	if want, have := "1234", errorInfo.GetMetadata()[CorrelationIDMetadataKey]; want != have {
+		t.Errorf("unexpected correlation ID\nexpected: %s\nactual:   %s", want, have)
+	}
+}
+
+func TestStatusErrorResponseEncoder_CorrelationIDErrorInfo(t *testing.T) {
+	st, _ := status.New(codes.NotFound, "not found").WithDetails(&errdetails.ErrorInfo{Reason: "ITEM_NOT_FOUND", Domain: "example.com"})
+
+	errorEncoder := NewStatusErrorResponseEncoder(statusConverterStub{st})
+
+	err := errorEncoder(correlation.ToContext(context.Background(), "1234"), errors.New("error"))
+
+	details := status.Convert(err).Details()
+	if len(details) != 1 {
+		t.Fatalf("unexpected number of details\nexpected: %d\nactual:   %d", 1, len(details))
+	}
+
+	errorInfo, ok := details[0].(*errdetails.ErrorInfo)
+	if !ok {
+		t.Fatalf("unexpected detail type: %T", details[0])
+	}
+
+	if want, have := "ITEM_NOT_FOUND", errorInfo.GetReason(); want != have {
+		t.Errorf("unexpected reason\nexpected: %s\nactual:   %s", want, have)
+	}
+
+	if want, have := "1234", errorInfo.GetMetadata()[CorrelationIDMetadataKey]; want != have {
+		t.Errorf("unexpected correlation ID\nexpected: %s\nactual:   %s", want, have)
+	}
+}
+
+func TestErrorResponseEncoder_Failure(t *testing.T) {
+	encoder := ErrorResponseEncoder(
+		func(_ context.Context, resp interface{}) (interface{}, error) { return resp, nil },
+		NewDefaultStatusErrorResponseEncoder(),
+	)
+
+	_, err := encoder(
+		correlation.ToContext(context.Background(), "1234"),
+		kitxendpoint.NewFailure(errors.New("error"), kitxendpoint.WithGRPCCode(codes.AlreadyExists)),
+	)
+
+	s := status.Convert(err)
+
+	if want, have := codes.AlreadyExists, s.Code(); want != have {
+		t.Errorf("unexpected code\nexpected: %d\nactual:   %d", want, have)
+	}
+
+	if want, have := 1, len(s.Details()); want != have {
+		t.Errorf("status details are expected to be preserved\nexpected: %d\nactual:   %d", want, have)
+	}
+}
@@ -1,7 +1,14 @@
 package grpc
 
 import (
+	"errors"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/durationpb"
+
+	kitxendpoint "github.com/sagikazarmark/kitx/endpoint"
 )
 
 // IsGRPCError checks if an error is already encoded as a gRPC status.
@@ -13,3 +20,37 @@ func IsGRPCError(err error) bool {
 
 	return ok
 }
+
+// StatusFromError returns the gRPC status of errors returned by kitx endpoint middleware
+// (DeadlineExceeded for *endpoint.TimeoutError, Unavailable for *endpoint.CircuitOpenError,
+// ResourceExhausted with a RetryInfo detail for *endpoint.RateLimitError, Internal for *endpoint.PanicError).
+// It can be used in custom StatusConverter implementations.
+func StatusFromError(err error) (*status.Status, bool) {
+	var timeoutErr *kitxendpoint.TimeoutError
+	if errors.As(err, &timeoutErr) {
+		return status.New(codes.DeadlineExceeded, "deadline exceeded"), true
+	}
+
+	var circuitOpenErr *kitxendpoint.CircuitOpenError
+	if errors.As(err, &circuitOpenErr) {
+		return status.New(codes.Unavailable, "service unavailable"), true
+	}
+
+	var rateLimitErr *kitxendpoint.RateLimitError
+	if errors.As(err, &rateLimitErr) {
+		st := status.New(codes.ResourceExhausted, "rate limit exceeded")
+
+		if s, err := st.WithDetails(&errdetails.RetryInfo{RetryDelay: durationpb.New(rateLimitErr.RetryAfter)}); err == nil {
+			st = s
+		}
+
+		return st, true
+	}
+
+	var panicErr *kitxendpoint.PanicError
+	if errors.As(err, &panicErr) {
+		return status.New(codes.Internal, "internal error"), true
+	}
+
+	return nil, false
+}
@@ -0,0 +1,104 @@
+package grpc
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	kitxendpoint "github.com/sagikazarmark/kitx/endpoint"
+)
+
+func TestStatusFromError(t *testing.T) {
+	tests := []struct {
+		err  error
+		code codes.Code
+	}{
+		{
+			err:  &kitxendpoint.TimeoutError{Operation: "greet"},
+			code: codes.DeadlineExceeded,
+		},
+		{
+			err:  &kitxendpoint.CircuitOpenError{Operation: "greet"},
+			code: codes.Unavailable,
+		},
+		{
+			err:  &kitxendpoint.PanicError{Operation: "greet", Value: "secret"},
+			code: codes.Internal,
+		},
+	}
+
+	for _, test := range tests {
+		st, ok := StatusFromError(test.err)
+		if !ok {
+			t.Errorf("error is expected to be converted: %v", test.err)
+
+			continue
+		}
+
+		if want, have := test.code, st.Code(); want != have {
+			t.Errorf("unexpected code\nexpected: %d\nactual:   %d", want, have)
+		}
+	}
+
+	if _, ok := StatusFromError(errors.New("error")); ok {
+		t.Error("unknown errors are not expected to be converted")
+	}
+}
+
+func TestDefaultStatusErrorResponseEncoder_Timeout(t *testing.T) {
+	errorEncoder := NewDefaultStatusErrorResponseEncoder()
+
+	err := errorEncoder(context.Background(), &kitxendpoint.TimeoutError{Operation: "greet"})
+
+	if want, have := codes.DeadlineExceeded, status.Code(err); want != have {
+		t.Errorf("unexpected code\nexpected: %d\nactual:   %d", want, have)
+	}
+}
+
+func TestDefaultStatusErrorResponseEncoder_RateLimit(t *testing.T) {
+	errorEncoder := NewDefaultStatusErrorResponseEncoder()
+
+	err := errorEncoder(context.Background(), &kitxendpoint.RateLimitError{Operation: "greet", RetryAfter: time.Second})
+
+	s := status.Convert(err)
+
+	if want, have := codes.ResourceExhausted, s.Code(); want != have {
+		t.Errorf("unexpected code\nexpected: %d\nactual:   %d", want, have)
+	}
+
+	details := s.Details()
+	if len(details) != 1 {
+		t.Fatalf("unexpected number of details\nexpected: %d\nactual:   %d", 1, len(details))
+	}
+
+	retryInfo, ok := details[0].(*errdetails.RetryInfo)
+	if !ok {
+		t.Fatalf("unexpected detail type: %T", details[0])
+	}
+
+	if want, have := time.Second, retryInfo.GetRetryDelay().AsDuration(); want != have {
+		t.Errorf("unexpected retry delay\nexpected: %s\nactual:   %s", want, have)
+	}
+}
+
+func TestDefaultStatusErrorResponseEncoder_Panic(t *testing.T) {
+	errorEncoder := NewDefaultStatusErrorResponseEncoder()
+
+	err := errorEncoder(context.Background(), &kitxendpoint.PanicError{Operation: "greet", Value: "secret"})
+
+	s := status.Convert(err)
+
+	if want, have := codes.Internal, s.Code(); want != have {
+		t.Errorf("unexpected code\nexpected: %d\nactual:   %d", want, have)
+	}
+
+	if strings.Contains(s.Message(), "secret") {
+		t.Errorf("status message is not expected to contain the panic value: %s", s.Message())
+	}
+}
@@ -1,6 +1,7 @@
 package http
 
 import (
+	"bytes"
 	"context"
 	"encoding/json"
 	"encoding/xml"
@@ -10,6 +11,9 @@ import (
 	kithttp "github.com/go-kit/kit/transport/http"
 	"github.com/moogar0880/problems"
 	"github.com/pkg/errors"
+
+	"github.com/sagikazarmark/kitx/correlation"
+	kitxendpoint "github.com/sagikazarmark/kitx/endpoint"
 )
 
 // NopResponseEncoder can be used for operations without output parameters.
@@ -68,11 +72,32 @@ func (s statusCodeResponseWrapper) MarshalJSON() ([]byte, error) {
 type EncodeErrorResponseFunc func(context.Context, http.ResponseWriter, error) error
 
 // ErrorResponseEncoder encodes the passed response object to the HTTP response writer in JSON format.
+//
+// Failed responses are encoded using the error encoder.
+// If the response is a *endpoint.Failure, its headers are added to the response
+// and its HTTP status (if any) overrides the status code written by the error encoder.
+// The error encoder receives the failure error as is, and the *endpoint.Failure in the context
+// (see FailureFromContext), so it can honor the suggested HTTP status in the response body.
+// Partial response data (endpoint.Failure.Data) is not encoded.
 func ErrorResponseEncoder(
 	encoder kithttp.EncodeResponseFunc,
 	errorEncoder EncodeErrorResponseFunc,
 ) kithttp.EncodeResponseFunc {
 	return func(ctx context.Context, w http.ResponseWriter, resp interface{}) error {
+		if failure, ok := kitxendpoint.AsFailure(resp); ok && failure.Failed() != nil {
+			for key, values := range failure.Header {
+				for _, value := range values {
+					w.Header().Add(key, value)
+				}
+			}
+
+			if failure.HTTPStatus != 0 {
+				w = &statusCodeWriter{ResponseWriter: w, code: failure.HTTPStatus}
+			}
+
+			return errorEncoder(contextWithFailure(ctx, failure), w, failure.Failed())
+		}
+
 		if f, ok := resp.(endpoint.Failer); ok && f.Failed() != nil {
 			return errorEncoder(ctx, w, f.Failed())
 		}
@@ -81,6 +106,29 @@ func ErrorResponseEncoder(
 	}
 }
 
+// statusCodeWriter overrides the status code written to the response.
+type statusCodeWriter struct {
+	http.ResponseWriter
+
+	code        int
+	wroteHeader bool
+}
+
+func (w *statusCodeWriter) WriteHeader(_ int) {
+	if w.wroteHeader {
+		return
+	}
+
+	w.wroteHeader = true
+	w.ResponseWriter.WriteHeader(w.code)
+}
+
+func (w *statusCodeWriter) Write(b []byte) (int, error) {
+	w.WriteHeader(w.code)
+
+	return w.ResponseWriter.Write(b)
+}
+
 func errorResponseEncoderWrapper(errorEncoder EncodeErrorResponseFunc) kithttp.ErrorEncoder {
 	return func(ctx context.Context, err error, w http.ResponseWriter) {
 		_ = errorEncoder(ctx, w, err)
@@ -95,33 +143,143 @@ type ProblemConverter interface {
 
 type defaultErrorProblemConverter struct{}
 
-func (d defaultErrorProblemConverter) NewProblem(_ context.Context, _ error) interface{} {
+func (d defaultErrorProblemConverter) NewProblem(ctx context.Context, err error) interface{} {
+	if failure, ok := FailureFromContext(ctx); ok && failure.HTTPStatus != 0 {
+		return problems.NewStatusProblem(failure.HTTPStatus)
+	}
+
+	if code, ok := StatusCodeFromError(err); ok {
+		return problems.NewStatusProblem(code)
+	}
+
 	return problems.NewDetailedProblem(http.StatusInternalServerError, "something went wrong")
 }
 
 // NewJSONProblemErrorResponseEncoder returns an error response encoder that encodes errors following the
 // RFC-7807 (Problem Details) standard (in JSON format).
 //
+// If the context contains a correlation ID, it is added to the response as a header (see ProblemCorrelationIDHeader)
+// and to the problem as a "correlationId" extension member.
+// Rate limit errors (see endpoint.RateLimitError) set the Retry-After header.
+//
 // See details at https://tools.ietf.org/html/rfc7807
-func NewJSONProblemErrorResponseEncoder(problemConverter ProblemConverter) EncodeErrorResponseFunc {
+func NewJSONProblemErrorResponseEncoder(problemConverter ProblemConverter, opts ...ProblemEncoderOption) EncodeErrorResponseFunc {
+	o := newProblemEncoderOptions(opts)
+
 	return func(ctx context.Context, w http.ResponseWriter, err error) error {
 		problem := problemConverter.NewProblem(ctx, err)
 
 		w.Header().Set("Content-Type", problems.ProblemMediaType)
+		o.setCorrelationIDHeader(ctx, w)
+		setRetryAfterHeader(w, err)
 		if s, ok := problem.(problems.StatusProblem); ok && s.ProblemStatus() != 0 {
 			w.WriteHeader(s.ProblemStatus())
 		}
 
+		if cid, ok := correlation.FromContext(ctx); ok && cid != "" {
+			problem = correlatedProblem{problem: problem, correlationID: cid}
+		}
+
 		return errors.WithStack(json.NewEncoder(w).Encode(problem))
 	}
 }
 
+// ProblemEncoderOption configures problem error encoders.
+type ProblemEncoderOption interface {
+	apply(o *problemEncoderOptions)
+}
+
+type problemEncoderOptions struct {
+	correlationIDHeader string
+}
+
+type problemEncoderOptionFunc func(o *problemEncoderOptions)
+
+func (fn problemEncoderOptionFunc) apply(o *problemEncoderOptions) {
+	fn(o)
+}
+
+// ProblemCorrelationIDHeader sets the response header containing the correlation ID.
+// It should match the header configured in correlation.HTTPToResponse. An empty header disables it.
+//
+// By default, the correlation ID is set in the correlation.DefaultHeader header.
+func ProblemCorrelationIDHeader(header string) ProblemEncoderOption {
+	return problemEncoderOptionFunc(func(o *problemEncoderOptions) { o.correlationIDHeader = header })
+}
+
+func newProblemEncoderOptions(opts []ProblemEncoderOption) problemEncoderOptions {
+	o := problemEncoderOptions{
+		correlationIDHeader: correlation.DefaultHeader,
+	}
+
+	for _, opt := range opts {
+		opt.apply(&o)
+	}
+
+	return o
+}
+
+func (o problemEncoderOptions) setCorrelationIDHeader(ctx context.Context, w http.ResponseWriter) {
+	if o.correlationIDHeader == "" {
+		return
+	}
+
+	if cid, ok := correlation.FromContext(ctx); ok && cid != "" {
+		w.Header().Set(o.correlationIDHeader, cid)
+	}
+}
+
+// correlatedProblem adds the correlation ID to a problem as an extension member.
+type correlatedProblem struct {
+	problem       interface{}
+	correlationID string
+}
+
+func (p correlatedProblem) MarshalJSON() ([]byte, error) {
+	b, err := json.Marshal(p.problem)
+	if err != nil {
+		return nil, err
+	}
+
+	b = bytes.TrimSpace(b)
+
+	// Only JSON objects can be extended
+	var members map[string]json.RawMessage
+	if err := json.Unmarshal(b, &members); err != nil || members == nil {
+		return b, nil
+	}
+
+	// Do not override an existing member
+	if _, ok := members["correlationId"]; ok {
+		return b, nil
+	}
+
+	cid, err := json.Marshal(p.correlationID)
+	if err != nil {
+		return nil, err
+	}
+
+	extension := append([]byte(`"correlationId":`), cid...)
+
+	if len(members) > 0 {
+		extension = append([]byte{','}, extension...)
+	}
+
+	out := make([]byte, 0, len(b)+len(extension))
+	out = append(out, b[:len(b)-1]...)
+	out = append(out, extension...)
+
+	return append(out, '}'), nil
+}
+
 // NewDefaultJSONProblemErrorResponseEncoder returns an error response encoder that encodes errors following the
 // RFC-7807 (Problem Details) standard (in JSON format).
 //
 // See details at https://tools.ietf.org/html/rfc7807
 //
-// The returned encoder encodes every error as 500 Internal Server Error.
+// The returned encoder encodes every error as 500 Internal Server Error,
+// except errors returned by kitx endpoint middleware (see StatusCodeFromError)
+// and failures with a suggested status (see FailureFromContext).
 func NewDefaultJSONProblemErrorResponseEncoder() EncodeErrorResponseFunc {
 	return NewJSONProblemErrorResponseEncoder(defaultErrorProblemConverter{})
 }
@@ -129,12 +287,19 @@ func NewDefaultJSONProblemErrorResponseEncoder() EncodeErrorResponseFunc {
 // NewXMLProblemErrorResponseEncoder returns an error response encoder that encodes errors following the
 // RFC-7807 (Problem Details) standard (in XML format).
 //
+// If the context contains a correlation ID, it is added to the response as a header (see ProblemCorrelationIDHeader).
+// Rate limit errors (see endpoint.RateLimitError) set the Retry-After header.
+//
 // See details at https://tools.ietf.org/html/rfc7807
-func NewXMLProblemErrorResponseEncoder(problemConverter ProblemConverter) EncodeErrorResponseFunc {
+func NewXMLProblemErrorResponseEncoder(problemConverter ProblemConverter, opts ...ProblemEncoderOption) EncodeErrorResponseFunc {
+	o := newProblemEncoderOptions(opts)
+
 	return func(ctx context.Context, w http.ResponseWriter, err error) error {
 		problem := problemConverter.NewProblem(ctx, err)
 
 		w.Header().Set("Content-Type", problems.ProblemMediaTypeXML)
+		o.setCorrelationIDHeader(ctx, w)
+		setRetryAfterHeader(w, err)
 		if s, ok := problem.(problems.StatusProblem); ok && s.ProblemStatus() != 0 {
 			w.WriteHeader(s.ProblemStatus())
 		}
@@ -148,7 +313,9 @@ func NewXMLProblemErrorResponseEncoder(problemConverter ProblemConverter) Encode
 //
 // See details at https://tools.ietf.org/html/rfc7807
 //
-// The returned encoder encodes every error as 500 Internal Server Error.
+// The returned encoder encodes every error as 500 Internal Server Error,
+// except errors returned by kitx endpoint middleware (see StatusCodeFromError)
+// and failures with a suggested status (see FailureFromContext).
 func NewDefaultXMLProblemErrorResponseEncoder() EncodeErrorResponseFunc {
 	return NewXMLProblemErrorResponseEncoder(defaultErrorProblemConverter{})
 }
@@ -157,8 +324,8 @@ func NewDefaultXMLProblemErrorResponseEncoder() EncodeErrorResponseFunc {
 // RFC-7807 (Problem Details) standard (in JSON format).
 //
 // See details at https://tools.ietf.org/html/rfc7807
-func NewJSONProblemErrorEncoder(problemConverter ProblemConverter) kithttp.ErrorEncoder {
-	return errorResponseEncoderWrapper(NewJSONProblemErrorResponseEncoder(problemConverter))
+func NewJSONProblemErrorEncoder(problemConverter ProblemConverter, opts ...ProblemEncoderOption) kithttp.ErrorEncoder {
+	return errorResponseEncoderWrapper(NewJSONProblemErrorResponseEncoder(problemConverter, opts...))
 }
 
 // NewDefaultJSONProblemErrorEncoder returns an error encoder that encodes errors following the
@@ -166,7 +333,8 @@ func NewJSONProblemErrorEncoder(problemConverter ProblemConverter) kithttp.Error
 //
 // See details at https://tools.ietf.org/html/rfc7807
 //
-// The returned encoder encodes every error as 500 Internal Server Error.
+// The returned encoder encodes every error as 500 Internal Server Error,
+// except errors returned by kitx endpoint middleware (see StatusCodeFromError).
 func NewDefaultJSONProblemErrorEncoder() kithttp.ErrorEncoder {
 	return errorResponseEncoderWrapper(NewDefaultJSONProblemErrorResponseEncoder())
 }
@@ -175,8 +343,8 @@ func NewDefaultJSONProblemErrorEncoder() kithttp.ErrorEncoder {
 // RFC-7807 (Problem Details) standard (in XML format).
 //
 // See details at https://tools.ietf.org/html/rfc7807
-func NewXMLProblemErrorEncoder(problemConverter ProblemConverter) kithttp.ErrorEncoder {
-	return errorResponseEncoderWrapper(NewXMLProblemErrorResponseEncoder(problemConverter))
+func NewXMLProblemErrorEncoder(problemConverter ProblemConverter, opts ...ProblemEncoderOption) kithttp.ErrorEncoder {
+	return errorResponseEncoderWrapper(NewXMLProblemErrorResponseEncoder(problemConverter, opts...))
 }
 
 // NewDefaultXMLProblemErrorEncoder returns an error encoder that encodes errors following the
@@ -184,7 +352,8 @@ func NewXMLProblemErrorEncoder(problemConverter ProblemConverter) kithttp.ErrorE
 //
 // See details at https://tools.ietf.org/html/rfc7807
 //
-// The returned encoder encodes every error as 500 Internal Server Error.
+// The returned encoder encodes every error as 500 Internal Server Error,
+// except errors returned by kitx endpoint middleware (see StatusCodeFromError).
 func NewDefaultXMLProblemErrorEncoder() kithttp.ErrorEncoder {
 	return errorResponseEncoderWrapper(NewDefaultXMLProblemErrorResponseEncoder())
 }
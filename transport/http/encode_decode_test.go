@@ -13,6 +13,9 @@ import (
 
 	kithttp "github.com/go-kit/kit/transport/http"
 	"github.com/moogar0880/problems"
+
+	"github.com/sagikazarmark/kitx/correlation"
+	kitxendpoint "github.com/sagikazarmark/kitx/endpoint"
 )
 
 func TestNopResponseEncoder(t *testing.T) {
@@ -198,6 +201,111 @@ func TestErrorResponseEncoder(t *testing.T) {
 			t.Errorf("unexpected body\nexpected: %s\nactual:   %s", want, have)
 		}
 	})
+
+	t.Run("failure", func(t *testing.T) {
+		handler := kithttp.NewServer(
+			func(context.Context, interface{}) (interface{}, error) {
+				return kitxendpoint.NewFailure(
+					errors.New("error"),
+					kitxendpoint.WithHTTPStatus(http.StatusConflict),
+					kitxendpoint.WithHeader("X-Reason", "duplicate"),
+				), nil
+			},
+			kithttp.NopRequestDecoder,
+			ErrorResponseEncoder(JSONResponseEncoder, func(i context.Context, w http.ResponseWriter, e error) error {
+				problem := problems.NewDetailedProblem(http.StatusBadRequest, e.Error())
+
+				w.Header().Set("Content-Type", problems.ProblemMediaType)
+				w.WriteHeader(problem.Status)
+
+				return json.NewEncoder(w).Encode(problem)
+			}),
+		)
+
+		server := httptest.NewServer(handler)
+		defer server.Close()
+
+		resp, err := http.Get(server.URL)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer resp.Body.Close()
+
+		if want, have := http.StatusConflict, resp.StatusCode; want != have {
+			t.Errorf("unexpected status code\nexpected: %d\nactual:   %d", want, have)
+		}
+
+		if want, have := "duplicate", resp.Header.Get("X-Reason"); want != have {
+			t.Errorf("unexpected header\nexpected: %s\nactual:   %s", want, have)
+		}
+	})
+
+	t.Run("failure/error", func(t *testing.T) {
+		errNotFound := errors.New("not found")
+
+		e := kitxendpoint.FailerMiddleware(func(err error) bool { return true })(
+			func(context.Context, interface{}) (interface{}, error) { return nil, errNotFound },
+		)
+
+		handler := kithttp.NewServer(
+			e,
+			kithttp.NopRequestDecoder,
+			ErrorResponseEncoder(JSONResponseEncoder, func(_ context.Context, w http.ResponseWriter, err error) error {
+				// nolint: errorlint
+				if err == errNotFound {
+					w.WriteHeader(http.StatusNotFound)
+
+					return nil
+				}
+
+				w.WriteHeader(http.StatusInternalServerError)
+
+				return nil
+			}),
+		)
+
+		server := httptest.NewServer(handler)
+		defer server.Close()
+
+		resp, err := http.Get(server.URL)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer resp.Body.Close()
+
+		if want, have := http.StatusNotFound, resp.StatusCode; want != have {
+			t.Errorf("unexpected status code\nexpected: %d\nactual:   %d", want, have)
+		}
+	})
+
+	t.Run("failure/problem", func(t *testing.T) {
+		handler := kithttp.NewServer(
+			func(context.Context, interface{}) (interface{}, error) {
+				return kitxendpoint.NewFailure(errors.New("error"), kitxendpoint.WithHTTPStatus(http.StatusConflict)), nil
+			},
+			kithttp.NopRequestDecoder,
+			ErrorResponseEncoder(JSONResponseEncoder, NewDefaultJSONProblemErrorResponseEncoder()),
+		)
+
+		server := httptest.NewServer(handler)
+		defer server.Close()
+
+		resp, err := http.Get(server.URL)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer resp.Body.Close()
+
+		if want, have := http.StatusConflict, resp.StatusCode; want != have {
+			t.Errorf("unexpected status code\nexpected: %d\nactual:   %d", want, have)
+		}
+
+		expectedBody := `{"type":"about:blank","title":"Conflict","status":409}`
+		buf, _ := io.ReadAll(resp.Body)
+		if want, have := expectedBody, strings.TrimSpace(string(buf)); want != have {
+			t.Errorf("unexpected body\nexpected: %s\nactual:   %s", want, have)
+		}
+	})
 }
 
 type problemConverterStub struct {
@@ -393,3 +501,58 @@ func TestNewDefaultXMLProblemErrorEncoder(t *testing.T) {
 		t.Errorf("unexpected detail\nexpected: %s\nactual:   %s", want, have)
 	}
 }
+
+func TestNewJSONProblemErrorEncoder_CorrelationID(t *testing.T) {
+	errorEncoder := NewDefaultJSONProblemErrorEncoder()
+
+	w := httptest.NewRecorder()
+
+	errorEncoder(correlation.ToContext(context.Background(), "1234"), errors.New("error"), w)
+
+	resp := w.Result()
+	defer resp.Body.Close()
+
+	if want, have := "1234", resp.Header.Get("Correlation-ID"); want != have {
+		t.Errorf("unexpected correlation ID header\nexpected: %s\nactual:   %s", want, have)
+	}
+
+	expectedBody := `{"type":"about:blank","title":"Internal Server Error","status":500,"detail":"something went wrong","correlationId":"1234"}`
+	buf, _ := io.ReadAll(resp.Body)
+	if want, have := expectedBody, strings.TrimSpace(string(buf)); want != have {
+		t.Errorf("unexpected body\nexpected: %s\nactual:   %s", want, have)
+	}
+}
+
+func TestNewXMLProblemErrorEncoder_CorrelationID(t *testing.T) {
+	errorEncoder := NewDefaultXMLProblemErrorEncoder()
+
+	w := httptest.NewRecorder()
+
+	errorEncoder(correlation.ToContext(context.Background(), "1234"), errors.New("error"), w)
+
+	resp := w.Result()
+	defer resp.Body.Close()
+
+	if want, have := "1234", resp.Header.Get("Correlation-ID"); want != have {
+		t.Errorf("unexpected correlation ID header\nexpected: %s\nactual:   %s", want, have)
+	}
+}
+
+func TestNewJSONProblemErrorEncoder_CorrelationIDHeader(t *testing.T) {
+	errorEncoder := NewJSONProblemErrorEncoder(defaultErrorProblemConverter{}, ProblemCorrelationIDHeader("X-Correlation-ID"))
+
+	w := httptest.NewRecorder()
+
+	errorEncoder(correlation.ToContext(context.Background(), "1234"), errors.New("error"), w)
+
+	resp := w.Result()
+	defer resp.Body.Close()
+
+	if want, have := "1234", resp.Header.Get("X-Correlation-ID"); want != have {
+		t.Errorf("unexpected correlation ID header\nexpected: %s\nactual:   %s", want, have)
+	}
+
+	if have := resp.Header.Get("Correlation-ID"); have != "" {
+		t.Errorf("unexpected default correlation ID header: %s", have)
+	}
+}
@@ -0,0 +1,78 @@
+package http
+
+import (
+	"context"
+	"math"
+	"net/http"
+	"strconv"
+
+	"github.com/pkg/errors"
+
+	kitxendpoint "github.com/sagikazarmark/kitx/endpoint"
+)
+
+// StatusCodeFromError returns the HTTP status code of errors returned by kitx endpoint middleware
+// (504 Gateway Timeout for *endpoint.TimeoutError, 503 Service Unavailable for *endpoint.CircuitOpenError,
+// 429 Too Many Requests for *endpoint.RateLimitError, 500 Internal Server Error for *endpoint.PanicError).
+// The suggested status of an *endpoint.Failure in the error chain takes precedence.
+// It can be used in custom ProblemConverter implementations.
+func StatusCodeFromError(err error) (int, bool) {
+	var failure *kitxendpoint.Failure
+	if errors.As(err, &failure) && failure.HTTPStatus != 0 {
+		return failure.HTTPStatus, true
+	}
+
+	var timeoutErr *kitxendpoint.TimeoutError
+	if errors.As(err, &timeoutErr) {
+		return http.StatusGatewayTimeout, true
+	}
+
+	var circuitOpenErr *kitxendpoint.CircuitOpenError
+	if errors.As(err, &circuitOpenErr) {
+		return http.StatusServiceUnavailable, true
+	}
+
+	var rateLimitErr *kitxendpoint.RateLimitError
+	if errors.As(err, &rateLimitErr) {
+		return http.StatusTooManyRequests, true
+	}
+
+	var panicErr *kitxendpoint.PanicError
+	if errors.As(err, &panicErr) {
+		return http.StatusInternalServerError, true
+	}
+
+	return 0, false
+}
+
+type contextKey string
+
+// failureContextKey holds the key used to store the failure being encoded in the context.
+const failureContextKey contextKey = "failure"
+
+func contextWithFailure(ctx context.Context, failure *kitxendpoint.Failure) context.Context {
+	return context.WithValue(ctx, failureContextKey, failure)
+}
+
+// FailureFromContext returns the *endpoint.Failure being encoded by ErrorResponseEncoder (if any).
+// It can be used in custom error encoders and ProblemConverter implementations to honor the suggested HTTP status.
+func FailureFromContext(ctx context.Context) (*kitxendpoint.Failure, bool) {
+	failure, ok := ctx.Value(failureContextKey).(*kitxendpoint.Failure)
+
+	return failure, ok && failure != nil
+}
+
+// setRetryAfterHeader sets the Retry-After header (in seconds) for rate limit errors.
+func setRetryAfterHeader(w http.ResponseWriter, err error) {
+	var rateLimitErr *kitxendpoint.RateLimitError
+	if !errors.As(err, &rateLimitErr) {
+		return
+	}
+
+	seconds := int64(math.Ceil(rateLimitErr.RetryAfter.Seconds()))
+	if seconds < 1 {
+		seconds = 1
+	}
+
+	w.Header().Set("Retry-After", strconv.FormatInt(seconds, 10))
+}
@@ -0,0 +1,138 @@
+package http
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	kithttp "github.com/go-kit/kit/transport/http"
+	"github.com/moogar0880/problems"
+
+	kitxendpoint "github.com/sagikazarmark/kitx/endpoint"
+)
+
+func TestStatusCodeFromError(t *testing.T) {
+	tests := []struct {
+		err  error
+		code int
+		ok   bool
+	}{
+		{
+			err:  &kitxendpoint.TimeoutError{Operation: "greet"},
+			code: http.StatusGatewayTimeout,
+			ok:   true,
+		},
+		{
+			err:  fmt.Errorf("wrapped: %w", &kitxendpoint.TimeoutError{Operation: "greet"}),
+			code: http.StatusGatewayTimeout,
+			ok:   true,
+		},
+		{
+			err:  &kitxendpoint.CircuitOpenError{Operation: "greet"},
+			code: http.StatusServiceUnavailable,
+			ok:   true,
+		},
+		{
+			err:  &kitxendpoint.RateLimitError{Operation: "greet"},
+			code: http.StatusTooManyRequests,
+			ok:   true,
+		},
+		{
+			err:  &kitxendpoint.PanicError{Operation: "greet", Value: "secret"},
+			code: http.StatusInternalServerError,
+			ok:   true,
+		},
+		{
+			err:  kitxendpoint.NewFailure(&kitxendpoint.TimeoutError{Operation: "greet"}, kitxendpoint.WithHTTPStatus(http.StatusConflict)),
+			code: http.StatusConflict,
+			ok:   true,
+		},
+		{
+			err: kitxendpoint.NewFailure(errors.New("error")),
+		},
+		{
+			err: errors.New("error"),
+		},
+	}
+
+	for _, test := range tests {
+		code, ok := StatusCodeFromError(test.err)
+
+		if want, have := test.ok, ok; want != have {
+			t.Errorf("unexpected result for %q\nexpected: %v\nactual:   %v", test.err, want, have)
+		}
+
+		if want, have := test.code, code; want != have {
+			t.Errorf("unexpected status code for %q\nexpected: %d\nactual:   %d", test.err, want, have)
+		}
+	}
+}
+
+func TestNewDefaultJSONProblemErrorEncoder_Timeout(t *testing.T) {
+	errorEncoder := NewDefaultJSONProblemErrorEncoder()
+
+	w := httptest.NewRecorder()
+
+	errorEncoder(context.Background(), &kitxendpoint.TimeoutError{Operation: "greet"}, w)
+
+	resp := w.Result()
+	defer resp.Body.Close()
+
+	testStatusAndContentType(t, resp, http.StatusGatewayTimeout, problems.ProblemMediaType)
+}
+
+func TestNewDefaultProblemErrorEncoder_RateLimit(t *testing.T) {
+	encoders := map[string]kithttp.ErrorEncoder{
+		"json": NewDefaultJSONProblemErrorEncoder(),
+		"xml":  NewDefaultXMLProblemErrorEncoder(),
+	}
+
+	for name, errorEncoder := range encoders {
+		errorEncoder := errorEncoder
+
+		t.Run(name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+
+			errorEncoder(context.Background(), &kitxendpoint.RateLimitError{Operation: "greet", RetryAfter: 1500 * time.Millisecond}, w)
+
+			resp := w.Result()
+			defer resp.Body.Close()
+
+			if want, have := http.StatusTooManyRequests, resp.StatusCode; want != have {
+				t.Errorf("unexpected status code\nexpected: %d\nactual:   %d", want, have)
+			}
+
+			if want, have := "2", resp.Header.Get("Retry-After"); want != have {
+				t.Errorf("unexpected Retry-After header\nexpected: %s\nactual:   %s", want, have)
+			}
+		})
+	}
+}
+
+func TestNewDefaultJSONProblemErrorEncoder_Panic(t *testing.T) {
+	errorEncoder := NewDefaultJSONProblemErrorEncoder()
+
+	w := httptest.NewRecorder()
+
+	errorEncoder(context.Background(), &kitxendpoint.PanicError{Operation: "greet", Value: "secret"}, w)
+
+	resp := w.Result()
+	defer resp.Body.Close()
+
+	testStatusAndContentType(t, resp, http.StatusInternalServerError, problems.ProblemMediaType)
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if strings.Contains(string(body), "secret") {
+		t.Errorf("response is not expected to contain the panic value: %s", body)
+	}
+}
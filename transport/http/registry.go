@@ -0,0 +1,29 @@
+package http
+
+import (
+	"encoding/json"
+	"net/http"
+
+	kitxendpoint "github.com/sagikazarmark/kitx/endpoint"
+)
+
+// NewRegistryHandler returns an HTTP handler that serves the operations recorded in a registry as JSON.
+// It can be used for debugging and by documentation tooling.
+func NewRegistryHandler(registry *kitxendpoint.Registry) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet && r.Method != http.MethodHead {
+			w.Header().Set("Allow", "GET, HEAD")
+			w.WriteHeader(http.StatusMethodNotAllowed)
+
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json; charset=utf-8")
+
+		_ = json.NewEncoder(w).Encode(struct {
+			Operations []kitxendpoint.Operation `json:"operations"`
+		}{
+			Operations: registry.Operations(),
+		})
+	})
+}
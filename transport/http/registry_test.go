@@ -0,0 +1,45 @@
+package http
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	kitxendpoint "github.com/sagikazarmark/kitx/endpoint"
+)
+
+func TestNewRegistryHandler(t *testing.T) {
+	registry := kitxendpoint.NewRegistry()
+
+	f := kitxendpoint.NewFactoryWithOptions(kitxendpoint.FactoryRegistry(registry))
+	f.NewEndpoint("greet", func(context.Context, interface{}) (interface{}, error) { return nil, nil })
+
+	registry.SetMetadata("greet", kitxendpoint.OperationMetadata{Tags: []string{"public"}})
+
+	handler := NewRegistryHandler(registry)
+
+	t.Run("get", func(t *testing.T) {
+		w := httptest.NewRecorder()
+
+		handler.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/", nil))
+
+		testStatusAndContentType(t, w.Result(), http.StatusOK, "application/json; charset=utf-8")
+
+		expectedBody := `{"operations":[{"name":"greet","middleware":[],"tags":["public"]}]}`
+		if want, have := expectedBody, strings.TrimSpace(w.Body.String()); want != have {
+			t.Errorf("unexpected body\nexpected: %s\nactual:   %s", want, have)
+		}
+	})
+
+	t.Run("post", func(t *testing.T) {
+		w := httptest.NewRecorder()
+
+		handler.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/", nil))
+
+		if want, have := http.StatusMethodNotAllowed, w.Code; want != have {
+			t.Errorf("unexpected status code\nexpected: %d\nactual:   %d", want, have)
+		}
+	})
+}
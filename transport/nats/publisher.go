@@ -0,0 +1,110 @@
+package nats
+
+import (
+	"context"
+	"time"
+
+	"github.com/go-kit/kit/endpoint"
+	kitnats "github.com/go-kit/kit/transport/nats"
+	"github.com/nats-io/nats.go"
+)
+
+// Requester sends a request message and waits for a reply.
+// It is implemented by *nats.Conn.
+type Requester interface {
+	RequestMsgWithContext(ctx context.Context, msg *nats.Msg) (*nats.Msg, error)
+}
+
+// Publisher wraps a NATS connection and provides a method that implements endpoint.Endpoint.
+//
+// Unlike go-kit's NATS Publisher, it sends the whole message (including headers) set up by the request encoder
+// and the before functions (eg. correlation.NATSFromContext).
+type Publisher struct {
+	requester Requester
+	subject   string
+	enc       kitnats.EncodeRequestFunc
+	dec       kitnats.DecodeResponseFunc
+	before    []kitnats.RequestFunc
+	after     []kitnats.PublisherResponseFunc
+	timeout   time.Duration
+}
+
+// NewPublisher constructs a usable Publisher for a single remote method.
+func NewPublisher(
+	requester Requester,
+	subject string,
+	enc kitnats.EncodeRequestFunc,
+	dec kitnats.DecodeResponseFunc,
+	options ...PublisherOption,
+) *Publisher {
+	p := &Publisher{
+		requester: requester,
+		subject:   subject,
+		enc:       enc,
+		dec:       dec,
+		timeout:   10 * time.Second,
+	}
+
+	for _, option := range options {
+		option.apply(p)
+	}
+
+	return p
+}
+
+// PublisherOption sets an optional parameter for publishers.
+type PublisherOption interface {
+	apply(p *Publisher)
+}
+
+type publisherOptionFunc func(p *Publisher)
+
+func (fn publisherOptionFunc) apply(p *Publisher) {
+	fn(p)
+}
+
+// PublisherBefore sets the RequestFuncs that are applied to the outgoing NATS message before it's sent.
+func PublisherBefore(before ...kitnats.RequestFunc) PublisherOption {
+	return publisherOptionFunc(func(p *Publisher) { p.before = append(p.before, before...) })
+}
+
+// PublisherAfter sets the PublisherResponseFuncs applied to the incoming NATS reply prior to it being decoded.
+func PublisherAfter(after ...kitnats.PublisherResponseFunc) PublisherOption {
+	return publisherOptionFunc(func(p *Publisher) { p.after = append(p.after, after...) })
+}
+
+// PublisherTimeout sets the available timeout for NATS requests.
+//
+// By default, requests time out after 10 seconds.
+func PublisherTimeout(timeout time.Duration) PublisherOption {
+	return publisherOptionFunc(func(p *Publisher) { p.timeout = timeout })
+}
+
+// Endpoint returns a usable endpoint that invokes the remote endpoint.
+func (p Publisher) Endpoint() endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		ctx, cancel := context.WithTimeout(ctx, p.timeout)
+		defer cancel()
+
+		msg := nats.NewMsg(p.subject)
+
+		if err := p.enc(ctx, msg, request); err != nil {
+			return nil, err
+		}
+
+		for _, f := range p.before {
+			ctx = f(ctx, msg)
+		}
+
+		resp, err := p.requester.RequestMsgWithContext(ctx, msg)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, f := range p.after {
+			ctx = f(ctx, resp)
+		}
+
+		return p.dec(ctx, resp)
+	}
+}
@@ -0,0 +1,98 @@
+package nats
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	kitnats "github.com/go-kit/kit/transport/nats"
+	"github.com/nats-io/nats.go"
+
+	"github.com/sagikazarmark/kitx/correlation"
+)
+
+// requesterStub records the sent message and replies with a fixed message.
+type requesterStub struct {
+	msg      *nats.Msg
+	deadline bool
+	reply    *nats.Msg
+	err      error
+}
+
+func (r *requesterStub) RequestMsgWithContext(ctx context.Context, msg *nats.Msg) (*nats.Msg, error) {
+	r.msg = msg
+	_, r.deadline = ctx.Deadline()
+
+	return r.reply, r.err
+}
+
+func TestPublisher(t *testing.T) {
+	requester := &requesterStub{reply: &nats.Msg{Data: []byte("pong")}}
+
+	var afterCalled bool
+
+	publisher := NewPublisher(
+		requester,
+		"subject",
+		kitnats.EncodeJSONRequest,
+		func(_ context.Context, msg *nats.Msg) (interface{}, error) { return string(msg.Data), nil },
+		PublisherBefore(correlation.NATSFromContext()),
+		PublisherAfter(func(ctx context.Context, _ *nats.Msg) context.Context {
+			afterCalled = true
+
+			return ctx
+		}),
+		PublisherTimeout(time.Second),
+	)
+
+	response, err := publisher.Endpoint()(correlation.ToContext(context.Background(), "1234"), "ping")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if want, have := "pong", response; want != have {
+		t.Errorf("unexpected response\nexpected: %v\nactual:   %v", want, have)
+	}
+
+	if want, have := "subject", requester.msg.Subject; want != have {
+		t.Errorf("unexpected subject\nexpected: %s\nactual:   %s", want, have)
+	}
+
+	if want, have := `"ping"`, string(requester.msg.Data); want != have {
+		t.Errorf("unexpected data\nexpected: %s\nactual:   %s", want, have)
+	}
+
+	if want, have := "1234", requester.msg.Header.Get(correlation.DefaultHeader); want != have {
+		t.Errorf("unexpected correlation ID header\nexpected: %s\nactual:   %s", want, have)
+	}
+
+	if !requester.deadline {
+		t.Error("request is supposed to have a deadline")
+	}
+
+	if !afterCalled {
+		t.Error("after functions are supposed to be called")
+	}
+}
+
+func TestPublisher_Error(t *testing.T) {
+	requester := &requesterStub{err: nats.ErrTimeout}
+
+	publisher := NewPublisher(
+		requester,
+		"subject",
+		kitnats.EncodeJSONRequest,
+		func(context.Context, *nats.Msg) (interface{}, error) {
+			t.Fatal("decoder is not supposed to be called")
+
+			return nil, nil
+		},
+	)
+
+	if _, err := publisher.Endpoint()(context.Background(), "ping"); !errors.Is(err, nats.ErrTimeout) {
+		t.Errorf("unexpected error\nexpected: %v\nactual:   %v", nats.ErrTimeout, err)
+	}
+}
+
+var _ Requester = (*nats.Conn)(nil)